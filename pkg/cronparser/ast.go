@@ -0,0 +1,264 @@
+package cronparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Span is the byte range [Start, End) of a part of the expression.
+type Span struct {
+	Start, End int
+}
+
+// Field is a time field as written, the comma separated terms it lists.
+type Field struct {
+	Kind  FieldKind
+	Terms []Term
+	Span  Span
+}
+
+// TermKind tells apart the forms a term of a field can take.
+type TermKind int
+
+const (
+	// TermValue is a value, range or stepped range, e.g. "5", "MON-FRI" or "10-40/5".
+	TermValue TermKind = iota
+
+	// TermAny is "*", on its own or stepped as in "*/15".
+	TermAny
+
+	// TermNoValue is the Quartz "?" of the day fields.
+	TermNoValue
+
+	// TermHash is the Jenkins H, e.g. "H", "H/15" or "H(0-29)".
+	TermHash
+
+	// TermRandom is the OpenBSD ~, e.g. "0~30" or "~".
+	TermRandom
+
+	// TermLast is the Quartz L: "L" or "L-3" in day of month, and "L" or
+	// "FRIL" in day of week.
+	TermLast
+
+	// TermLastWeekday is the Quartz "LW" of day of month.
+	TermLastWeekday
+
+	// TermNearestWeekday is the Quartz W of day of month, e.g. "15W".
+	TermNearestWeekday
+
+	// TermNth is the Quartz # of day of week, e.g. "FRI#3".
+	TermNth
+)
+
+// Term is one comma separated term of a field, e.g. "MON-FRI" or "*/15".
+type Term struct {
+	Kind  TermKind
+	Start *Value //nil for "*", "?", a plain "L" or "H", or a "~" with no start
+	End   *Value //nil unless a range, as in "1-5", "H(0-29)" or "0~30"
+	Step  *Value //nil unless stepped, as in "*/15"
+	Arg   *Value //the 3 in "L-3" and "FRI#3", otherwise nil
+	Span  Span
+}
+
+// Value is a number or name in a term, e.g. "5" or "Fri".
+type Value struct {
+	Text string //as written
+	Int  int    //the number it stands for, e.g. 5 for "Fri"
+	Span Span
+}
+
+// String renders the field in canonical form: numbers without leading zeros
+// and names upper cased, e.g. "MON-FRI,0" for "mon-fri,00".
+func (f Field) String() string {
+	terms := make([]string, len(f.Terms))
+	for i, term := range f.Terms {
+		terms[i] = term.String()
+	}
+
+	return strings.Join(terms, ",")
+}
+
+// String renders the term in canonical form, e.g. "*/5" for "*/05".
+func (t Term) String() string {
+	var expr string
+	switch t.Kind {
+	case TermAny:
+		expr = "*"
+	case TermNoValue:
+		expr = "?"
+	case TermHash:
+		expr = "H"
+		if t.Start != nil {
+			expr += "(" + t.Start.String() + "-" + t.End.String() + ")"
+		}
+	case TermRandom:
+		expr = t.Start.String() + "~" + t.End.String()
+	case TermLast:
+		if t.Arg != nil {
+			return "L-" + t.Arg.String()
+		}
+
+		expr = t.Start.String() + "L"
+	case TermLastWeekday:
+		expr = "LW"
+	case TermNearestWeekday:
+		expr = t.Start.String() + "W"
+	case TermNth:
+		expr = t.Start.String() + "#" + t.Arg.String()
+	default:
+		expr = t.Start.String()
+		if t.End != nil {
+			expr += "-" + t.End.String()
+		}
+	}
+
+	if t.Step != nil {
+		expr += "/" + t.Step.String()
+	}
+
+	return expr
+}
+
+// String renders the value in canonical form: a name upper cased, and a number
+// without leading zeros. A nil Value, such as the missing start of "~30", is "".
+func (v *Value) String() string {
+	if v == nil {
+		return ""
+	}
+
+	if len(v.Text) > 0 && isLetter(v.Text[0]) {
+		return strings.ToUpper(v.Text)
+	}
+
+	return strconv.Itoa(v.Int)
+}
+
+// newField builds the Field of kind written as fieldExpr at offset in the
+// expression. fieldExpr must have parsed already.
+func newField(kind FieldKind, fieldExpr string, offset int) Field {
+	tokens, _ := lex(fieldExpr)
+
+	field := Field{Kind: kind, Span: Span{offset, offset + len(fieldExpr)}}
+	terms, _, _ := splitTerms(tokens)
+	for _, term := range terms {
+		field.Terms = append(field.Terms, newTerm(kind, term, offset))
+	}
+
+	return field
+}
+
+// newTerm builds the Term written as the tokens of term, in a field found at
+// offset in the expression.
+func newTerm(kind FieldKind, term []token, offset int) Term {
+	first, last := term[0], term[len(term)-1]
+	t := Term{Kind: TermValue, Span: Span{offset + first.offset, offset + last.offset + len(last.text)}}
+
+	upper := make([]string, len(term))
+	for i, tok := range term {
+		upper[i] = strings.ToUpper(tok.text)
+	}
+
+	switch {
+	case matchTokens(term, tokenQuestion):
+		t.Kind = TermNoValue
+	case first.kind == tokenAsterisk:
+		t.Kind = TermAny
+		if len(term) == 3 {
+			t.Step = newValue(kind, term[2], offset)
+		}
+	case first.kind == tokenName && upper[0] == "H":
+		t.Kind = TermHash
+		rest := term[1:]
+		if len(rest) > 0 && rest[0].kind == tokenOpen {
+			t.Start, t.End = newValue(kind, rest[1], offset), newValue(kind, rest[3], offset)
+			rest = rest[5:]
+		}
+
+		if len(rest) == 2 {
+			t.Step = newValue(kind, rest[1], offset)
+		}
+	case indexToken(term, tokenTilde) != -1:
+		t.Kind = TermRandom
+		if first.kind != tokenTilde {
+			t.Start = newValue(kind, first, offset)
+		}
+
+		if last.kind != tokenTilde {
+			t.End = newValue(kind, last, offset)
+		}
+	case kind == FieldDayOfMonth && matchTokens(term, tokenName) && upper[0] == "L":
+		t.Kind = TermLast
+	case kind == FieldDayOfMonth && matchTokens(term, tokenName) && upper[0] == "LW":
+		t.Kind = TermLastWeekday
+	case kind == FieldDayOfMonth && matchTokens(term, tokenName, tokenHyphen, tokenNumber) && upper[0] == "L":
+		t.Kind = TermLast
+		t.Arg = newValue(kind, term[2], offset)
+	case kind == FieldDayOfMonth && matchTokens(term, tokenNumber, tokenName) && upper[1] == "W":
+		t.Kind = TermNearestWeekday
+		t.Start = newValue(kind, first, offset)
+	case kind == FieldDayOfWeek && matchTokens(term, tokenName) && upper[0] == "L":
+		t.Kind = TermLast
+	case kind == FieldDayOfWeek && matchTokens(term, tokenNumber, tokenName) && upper[1] == "L":
+		t.Kind = TermLast
+		t.Start = newValue(kind, first, offset)
+	case kind == FieldDayOfWeek && matchTokens(term, tokenName) && strings.HasSuffix(upper[0], "L"):
+		t.Kind = TermLast
+		first.text = first.text[:len(first.text)-1]
+		t.Start = newValue(kind, first, offset)
+	case kind == FieldDayOfWeek && indexToken(term, tokenHash) != -1:
+		t.Kind = TermNth
+		t.Start, t.Arg = newValue(kind, first, offset), newValue(kind, last, offset)
+	default:
+		if i := indexToken(term, tokenSlash); i != -1 {
+			t.Step = newValue(kind, term[i+1], offset)
+			term = term[:i]
+		}
+
+		t.Start = newValue(kind, term[0], offset)
+		if len(term) == 3 {
+			t.End = newValue(kind, term[2], offset)
+		}
+	}
+
+	return t
+}
+
+func newValue(kind FieldKind, tok token, offset int) *Value {
+	val, _ := formatValue(tok, kind.abbreviations())
+	return &Value{Text: tok.text, Int: val, Span: Span{offset + tok.offset, offset + tok.offset + len(tok.text)}}
+}
+
+// at moves every span of the field to span, as for the fields a macro such as
+// @daily stands for.
+func (f *Field) at(span Span) {
+	f.Span = span
+	for i := range f.Terms {
+		f.Terms[i].Span = span
+		for _, val := range []*Value{f.Terms[i].Start, f.Terms[i].End, f.Terms[i].Step, f.Terms[i].Arg} {
+			if val != nil {
+				val.Span = span
+			}
+		}
+	}
+}
+
+// copy returns a copy of the field that shares none of its terms or values.
+func (f Field) copy() Field {
+	terms := make([]Term, len(f.Terms))
+	for i, term := range f.Terms {
+		term.Start, term.End, term.Step, term.Arg = term.Start.copy(), term.End.copy(), term.Step.copy(), term.Arg.copy()
+		terms[i] = term
+	}
+
+	f.Terms = terms
+	return f
+}
+
+func (v *Value) copy() *Value {
+	if v == nil {
+		return nil
+	}
+
+	val := *v
+	return &val
+}
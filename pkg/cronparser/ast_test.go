@@ -0,0 +1,115 @@
+package cronparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewField(t *testing.T) {
+	testCases := []struct {
+		name     string
+		kind     FieldKind
+		expr     string
+		expected []Term
+	}{
+		{name: "value", kind: FieldMinute, expr: "5", expected: []Term{
+			{Kind: TermValue, Start: &Value{"5", 5, Span{10, 11}}, Span: Span{10, 11}}}},
+		{name: "list and range", kind: FieldDayOfWeek, expr: "0,Mon-Fri/2", expected: []Term{
+			{Kind: TermValue, Start: &Value{"0", 0, Span{10, 11}}, Span: Span{10, 11}},
+			{Kind: TermValue, Start: &Value{"Mon", 1, Span{12, 15}}, End: &Value{"Fri", 5, Span{16, 19}}, Step: &Value{"2", 2, Span{20, 21}}, Span: Span{12, 21}}}},
+		{name: "stepped asterisk", kind: FieldMinute, expr: "*/15", expected: []Term{
+			{Kind: TermAny, Step: &Value{"15", 15, Span{12, 14}}, Span: Span{10, 14}}}},
+		{name: "no value", kind: FieldDayOfMonth, expr: "?", expected: []Term{
+			{Kind: TermNoValue, Span: Span{10, 11}}}},
+		{name: "hash", kind: FieldMinute, expr: "H(0-29)/10", expected: []Term{
+			{Kind: TermHash, Start: &Value{"0", 0, Span{12, 13}}, End: &Value{"29", 29, Span{14, 16}}, Step: &Value{"10", 10, Span{18, 20}}, Span: Span{10, 20}}}},
+		{name: "random", kind: FieldMinute, expr: "~30", expected: []Term{
+			{Kind: TermRandom, End: &Value{"30", 30, Span{11, 13}}, Span: Span{10, 13}}}},
+		{name: "last days", kind: FieldDayOfMonth, expr: "L,L-3,LW,15W", expected: []Term{
+			{Kind: TermLast, Span: Span{10, 11}},
+			{Kind: TermLast, Arg: &Value{"3", 3, Span{14, 15}}, Span: Span{12, 15}},
+			{Kind: TermLastWeekday, Span: Span{16, 18}},
+			{Kind: TermNearestWeekday, Start: &Value{"15", 15, Span{19, 21}}, Span: Span{19, 22}}}},
+		{name: "last weekdays", kind: FieldDayOfWeek, expr: "L,5L,FRIL", expected: []Term{
+			{Kind: TermLast, Span: Span{10, 11}},
+			{Kind: TermLast, Start: &Value{"5", 5, Span{12, 13}}, Span: Span{12, 14}},
+			{Kind: TermLast, Start: &Value{"FRI", 5, Span{15, 18}}, Span: Span{15, 19}}}},
+		{name: "nth weekday", kind: FieldDayOfWeek, expr: "FRI#3", expected: []Term{
+			{Kind: TermNth, Start: &Value{"FRI", 5, Span{10, 13}}, Arg: &Value{"3", 3, Span{14, 15}}, Span: Span{10, 15}}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newField(tc.kind, tc.expr, 10)
+			expected := Field{Kind: tc.kind, Terms: tc.expected, Span: Span{10, 10 + len(tc.expr)}}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected %+v but got %+v", expected, got)
+			}
+		})
+	}
+}
+
+func TestScheduleFields(t *testing.T) {
+	t.Run("fields as written", func(t *testing.T) {
+		schedule, err := Parse("TZ=UTC 0,30 9-17 * jan MON cmd")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []Field{
+			{Kind: FieldMinute, Span: Span{7, 11}, Terms: []Term{
+				{Kind: TermValue, Start: &Value{"0", 0, Span{7, 8}}, Span: Span{7, 8}},
+				{Kind: TermValue, Start: &Value{"30", 30, Span{9, 11}}, Span: Span{9, 11}}}},
+			{Kind: FieldHour, Span: Span{12, 16}, Terms: []Term{
+				{Kind: TermValue, Start: &Value{"9", 9, Span{12, 13}}, End: &Value{"17", 17, Span{14, 16}}, Span: Span{12, 16}}}},
+			{Kind: FieldDayOfMonth, Span: Span{17, 18}, Terms: []Term{
+				{Kind: TermAny, Span: Span{17, 18}}}},
+			{Kind: FieldMonth, Span: Span{19, 22}, Terms: []Term{
+				{Kind: TermValue, Start: &Value{"jan", 1, Span{19, 22}}, Span: Span{19, 22}}}},
+			{Kind: FieldDayOfWeek, Span: Span{23, 26}, Terms: []Term{
+				{Kind: TermValue, Start: &Value{"MON", 1, Span{23, 26}}, Span: Span{23, 26}}}},
+		}
+
+		if got := schedule.Fields(); !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %+v but got %+v", expected, got)
+		}
+	})
+
+	t.Run("macro", func(t *testing.T) {
+		schedule, err := Parse("@daily cmd", WithSeconds())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := schedule.Fields()
+		if len(fields) != 6 || fields[0].Kind != FieldSecond {
+			t.Fatalf("expected second to day of week fields but got %+v", fields)
+		}
+
+		expected := Field{Kind: FieldHour, Span: Span{0, 6}, Terms: []Term{
+			{Kind: TermValue, Start: &Value{"0", 0, Span{0, 6}}, Span: Span{0, 6}}}}
+		if !reflect.DeepEqual(fields[2], expected) {
+			t.Errorf("expected %+v but got %+v", expected, fields[2])
+		}
+	})
+
+	t.Run("reboot", func(t *testing.T) {
+		schedule, err := Parse("@reboot cmd")
+		assertSuccess(t, schedule.Fields(), []Field(nil), err)
+	})
+
+	t.Run("changing the fields leaves the schedule", func(t *testing.T) {
+		schedule, err := Parse("0 0 1,15 * * cmd")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fields := schedule.Fields()
+		fields[0].Terms[0].Kind = TermRandom
+		fields[1].Terms[0].Start.Int = 12
+		fields[2].Terms = fields[2].Terms[:1]
+		fields[3].Kind = FieldYear
+		assertSuccess(t, schedule.Expression(), "0 0 1,15 * * cmd", nil)
+		assertSuccess(t, schedule.Fields()[1].Terms[0].Start.Int, 0, nil)
+	})
+}
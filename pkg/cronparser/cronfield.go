@@ -1,90 +1,422 @@
 package cronparser
 
 import (
-	"errors"
+	"math/rand"
 	"strconv"
 	"strings"
 )
 
 type cronField struct {
-	expr      string
+	tokens    []token
 	min       int
 	max       int
 	interval  int
 	valueList []int
+	spec      *daySpec
+	wrap      bool //whether a range may wrap around the end of the field
+	stepped   bool //whether the expression had a step, as in "5/15"
+	sunday    bool //whether a day of week range ending at 7 adds Sunday
 }
 
 const FRInitBounds = -1  //Initial Bounds of a Cron Field Range
 const FRInitInterval = 1 //Initial Interval for a Cron Field Range
 
-func NewCronField(expr string) *cronField {
-	return &cronField{expr: expr, min: FRInitBounds, max: FRInitBounds, interval: FRInitInterval}
+func NewCronField(tokens []token) *cronField {
+	//the handlers rewrite the tokens, so they get a copy of their own
+	tokens = append([]token{}, tokens...)
+	return &cronField{tokens: tokens, min: FRInitBounds, max: FRInitBounds, interval: FRInitInterval}
 }
 
-func (cf *cronField) handleSlash() (err error) {
-	exprList := strings.Split(cf.expr, "/")
-	if len(exprList) == 2 {
-		cf.interval, err = strconv.Atoi(exprList[1])
+// expr is what is left of the term as text, e.g. "0-59" once "*" is handled.
+func (cf cronField) expr() string {
+	return joinTokens(cf.tokens)
+}
+
+// handleHash handles the Jenkins H: "H" is one value picked by hash, "H/15"
+// steps from a hashed start and "H(0-29)" picks within the given range. Without
+// a range, a day of month H is kept to 1-28 so that it fires every month.
+func (cf *cronField) handleHash(bounds bound, hash *rand.Rand) (err error) {
+	if len(cf.tokens) == 0 || cf.tokens[0].kind != tokenName || cf.tokens[0].text != "H" {
+		return
+	}
+
+	if hash == nil {
+		err = ErrNoHashKey
+		return
+	}
+
+	at := cf.tokens[0].offset
+	low, high := bounds.min, bounds.max
+	if bounds == DOMBound {
+		high = HASH_DOM_MAX
+	}
+
+	rest := cf.tokens[1:]
+	if len(rest) > 0 && rest[0].kind == tokenOpen {
+		if len(rest) < 5 || !matchTokens(rest[:5], tokenOpen, tokenNumber, tokenHyphen, tokenNumber, tokenClose) {
+			err = ErrInvalidField
+			return
+		}
+
+		low, err = strconv.Atoi(rest[1].text)
+		if err != nil {
+			return
+		}
+
+		high, err = strconv.Atoi(rest[3].text)
+		if err != nil {
+			return
+		}
+
+		if low < bounds.min || high > maxValue(bounds) {
+			err = ErrOutOfBounds
+			return
+		}
+
+		if low > high {
+			err = ErrInvalidRange
+			return
+		}
+
+		rest = rest[5:]
+	}
+
+	switch {
+	case len(rest) == 0:
+		cf.tokens = []token{numberToken(low+hash.Intn(high-low+1), at)}
+	case matchTokens(rest, tokenSlash, tokenNumber):
+		var interval int
+		interval, err = strconv.Atoi(rest[1].text)
+		if err != nil {
+			return
+		}
+
+		if interval < 1 {
+			err = ErrInvalidStep
+			return
+		}
+
+		span := high - low + 1
+		if interval < span {
+			span = interval
+		}
+
+		cf.tokens = append([]token{numberToken(low+hash.Intn(span), at), {kind: tokenHyphen, text: "-", offset: at}, numberToken(high, at)}, rest...)
+	default:
+		err = ErrInvalidField
+	}
+
+	return
+}
+
+// handleRandom handles the OpenBSD ~: "0~30" is one value picked at random
+// between 0 and 30, and a missing end stands for the field's own bound. The
+// source is only asked for once a ~ is found, as seeding one isn't free.
+func (cf *cronField) handleRandom(bounds bound, abbreviationMap map[string]string, random func() *rand.Rand) (err error) {
+	i := indexToken(cf.tokens, tokenTilde)
+	if i == -1 {
+		return
+	}
+
+	before, after := cf.tokens[:i], cf.tokens[i+1:]
+	if len(before) > 1 || len(after) > 1 {
+		err = ErrInvalidField
+		return
+	}
+
+	low, high := bounds.min, bounds.max
+	if len(before) == 1 {
+		low, err = formatValue(before[0], abbreviationMap)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(after) == 1 {
+		high, err = formatValue(after[0], abbreviationMap)
 		if err != nil {
 			return
 		}
+	}
 
-		cf.expr = exprList[0]
+	if low < bounds.min || high > maxValue(bounds) {
+		err = ErrOutOfBounds
+		return
 	}
+
+	if low > high {
+		err = ErrInvalidRange
+		return
+	}
+
+	cf.tokens = []token{numberToken(low+random().Intn(high-low+1), cf.tokens[0].offset)}
+	return
+}
+
+// handleSlash takes the step off the end of the term, as in "*/15".
+func (cf *cronField) handleSlash() (err error) {
+	i := indexToken(cf.tokens, tokenSlash)
+	if i == -1 {
+		return
+	}
+
+	step := cf.tokens[i+1:]
+	switch {
+	case i == 0 || len(step) == 0:
+		err = &tokenError{tok: cf.tokens[i], err: ErrUnexpectedToken}
+		return
+	case step[0].kind != tokenNumber:
+		err = &tokenError{tok: step[0], err: ErrInvalidValue}
+		return
+	case len(step) > 1:
+		err = &tokenError{tok: step[1], err: ErrUnexpectedToken}
+		return
+	}
+
+	cf.interval, err = formatValue(step[0], nil)
+	if err != nil {
+		return
+	}
+
+	cf.tokens = cf.tokens[:i]
+	cf.stepped = true
 	return
 }
 
 func (cf *cronField) handleAsterisk(bounds bound) (err error) {
-	if cf.expr == "*" {
-		cf.expr = strconv.Itoa(bounds.min) + "-" + strconv.Itoa(bounds.max)
+	if matchTokens(cf.tokens, tokenAsterisk) {
+		at := cf.tokens[0].offset
+		cf.tokens = []token{numberToken(bounds.min, at), {kind: tokenHyphen, text: "-", offset: at}, numberToken(bounds.max, at)}
 	}
 	return
 }
 
-func (cf *cronField) handleSingleValue() (err error) {
-	exprList := strings.Split(cf.expr, "-")
-	if len(exprList) == 1 {
-		cf.expr = exprList[0] + "-" + exprList[0]
+// handleSingleValue turns a single value into a range: "5" is 5-5, while a
+// stepped "5/15" starts at 5 and runs to the end of the field.
+func (cf *cronField) handleSingleValue(bounds bound) (err error) {
+	if indexToken(cf.tokens, tokenHyphen) != -1 {
+		return
+	}
+
+	switch {
+	case len(cf.tokens) == 0:
+		err = ErrInvalidField
+		return
+	case len(cf.tokens) > 1:
+		err = &tokenError{tok: cf.tokens[1], err: ErrUnexpectedToken}
+		return
+	}
+
+	value := cf.tokens[0]
+	hyphen := token{kind: tokenHyphen, text: "-", offset: value.offset + len(value.text)}
+	if cf.stepped {
+		cf.tokens = []token{value, hyphen, numberToken(bounds.max, hyphen.offset)}
+		return
 	}
+
+	cf.tokens = []token{value, hyphen, value}
 	return
 }
 
+// handleHyphen reads the range "min-max" the term has come down to.
 func (cf *cronField) handleHyphen(abbreviationMap map[string]string) (err error) {
-	exprList := strings.Split(cf.expr, "-")
-	if len(exprList) == 2 {
-		cf.min, err = formatBound(exprList[0], abbreviationMap)
+	i := indexToken(cf.tokens, tokenHyphen)
+	switch {
+	case i == -1:
+		return
+	case i == 0:
+		//nothing before the hyphen, as in "-5"
+		err = &tokenError{tok: cf.tokens[0], err: ErrUnexpectedToken}
+		return
+	case i > 1:
+		//more than a value before it, as in "2*-5"
+		err = &tokenError{tok: cf.tokens[1], err: ErrUnexpectedToken}
+		return
+	case len(cf.tokens) == 2:
+		err = &tokenError{tok: cf.tokens[1], err: ErrUnexpectedToken}
+		return
+	case len(cf.tokens) > 3:
+		err = &tokenError{tok: cf.tokens[3], err: ErrUnexpectedToken}
+		return
+	}
+
+	cf.min, err = formatValue(cf.tokens[0], abbreviationMap)
+	if err != nil {
+		return
+	}
+
+	cf.max, err = formatValue(cf.tokens[2], abbreviationMap)
+	return
+}
+
+// handleSundayAlias lets day of week take 7 as Sunday, as Vixie cron does:
+// "7" is 0, and a range ending at 7 such as "5-7" is 5-6 and Sunday.
+func (cf *cronField) handleSundayAlias(bounds bound) (err error) {
+	if bounds != DOWBound {
+		return
+	}
+
+	//a range from 7 down, as in "7-2", is descending just as "6-0" is, while
+	//"7/2" runs on to the end of the week
+	descending := cf.min == SUNDAY_ALIAS && cf.max < SUNDAY_ALIAS && !(cf.stepped && cf.max == bounds.max)
+	if descending && !cf.wrap {
+		err = ErrInvalidRange
+		return
+	}
+
+	if cf.max == SUNDAY_ALIAS && cf.min < SUNDAY_ALIAS {
+		cf.sunday = cf.min > bounds.min && cf.interval > 0 && (SUNDAY_ALIAS-cf.min)%cf.interval == 0
+		cf.max = bounds.max
+	}
+
+	if cf.min == SUNDAY_ALIAS {
+		cf.min = bounds.min
+	}
+
+	if cf.max == SUNDAY_ALIAS {
+		cf.max = bounds.min
+	}
+
+	return
+}
+
+// handleLast handles the Quartz L: "L", "L-3" and "LW" in day of month, and
+// "5L" or "FRIL" (the last Friday) in day of week, where a plain "L" is Saturday.
+// Weekdays are numbered as in Unix cron, 0 being Sunday, not 1 as in Quartz.
+func (cf *cronField) handleLast(field FieldKind, abbreviationMap map[string]string) (err error) {
+	if cf.spec != nil || len(cf.tokens) == 0 {
+		return
+	}
+
+	last := cf.tokens[len(cf.tokens)-1]
+	switch {
+	case field == FieldDayOfMonth && matchTokens(cf.tokens, tokenName) && last.text == "L":
+		cf.spec = &daySpec{kind: specLastDay}
+	case field == FieldDayOfMonth && matchTokens(cf.tokens, tokenName) && last.text == "LW":
+		cf.spec = &daySpec{kind: specLastWeekday}
+	case field == FieldDayOfMonth && len(cf.tokens) > 1 && matchTokens(cf.tokens[:2], tokenName, tokenHyphen) && cf.tokens[0].text == "L":
+		if len(cf.tokens) != 3 {
+			err = &tokenError{tok: cf.tokens[len(cf.tokens)-1], err: ErrUnexpectedToken}
+			return
+		}
+
+		var offset int
+		offset, err = formatValue(cf.tokens[2], nil)
 		if err != nil {
 			return
 		}
 
-		cf.max, err = formatBound(exprList[1], abbreviationMap)
+		if offset < 0 || offset >= DOMBound.max {
+			err = ErrOutOfBounds
+			return
+		}
+
+		cf.spec = &daySpec{kind: specLastDay, value: offset}
+	case field == FieldDayOfWeek && matchTokens(cf.tokens, tokenName) && last.text == "L":
+		cf.tokens = []token{numberToken(DOWBound.max, last.offset)}
+	case field == FieldDayOfWeek && (matchTokens(cf.tokens, tokenNumber, tokenName) && last.text == "L" || matchTokens(cf.tokens, tokenName) && strings.HasSuffix(last.text, "L")):
+		//"5L", or a name with the L run into it, as in "FRIL"
+		weekday := cf.tokens[0]
+		if len(cf.tokens) == 1 {
+			weekday.text = strings.TrimSuffix(weekday.text, "L")
+		}
+
+		var value int
+		value, err = formatDayOfWeek(weekday, abbreviationMap)
 		if err != nil {
 			return
 		}
+
+		cf.spec = &daySpec{kind: specLastOfWeekday, value: value}
+	}
+
+	return
+}
+
+// handleNearestWeekday handles the Quartz W in day of month: "15W" is the
+// weekday closest to the 15th.
+func (cf *cronField) handleNearestWeekday(field FieldKind) (err error) {
+	if cf.spec != nil || field != FieldDayOfMonth || len(cf.tokens) == 0 {
+		return
+	}
+
+	if last := cf.tokens[len(cf.tokens)-1]; last.kind != tokenName || last.text != "W" {
+		return
+	}
+
+	if len(cf.tokens) != 2 {
+		err = &tokenError{tok: cf.tokens[len(cf.tokens)-1], err: ErrUnexpectedToken}
+		return
+	}
+
+	day, err := formatValue(cf.tokens[0], nil)
+	if err != nil {
+		return
+	}
+
+	if day < DOMBound.min || day > DOMBound.max {
+		err = ErrOutOfBounds
+		return
 	}
+
+	cf.spec = &daySpec{kind: specNearestWeekday, value: day}
+	return
+}
+
+// handleNth handles the Quartz # in day of week: "FRI#3" or "5#3" is the third
+// Friday of the month. Weekdays are numbered as in Unix cron, so the Quartz
+// "6#3" is the third Saturday here rather than the third Friday.
+func (cf *cronField) handleNth(field FieldKind, abbreviationMap map[string]string) (err error) {
+	if cf.spec != nil || field != FieldDayOfWeek || indexToken(cf.tokens, tokenHash) == -1 {
+		return
+	}
+
+	if len(cf.tokens) != 3 || cf.tokens[1].kind != tokenHash {
+		err = ErrInvalidField
+		return
+	}
+
+	weekday, err := formatDayOfWeek(cf.tokens[0], abbreviationMap)
+	if err != nil {
+		return
+	}
+
+	nth, err := formatValue(cf.tokens[2], nil)
+	if err != nil {
+		return
+	}
+
+	if nth < 1 || nth > 5 {
+		err = ErrOutOfBounds
+		return
+	}
+
+	cf.spec = &daySpec{kind: specNthOfWeekday, value: weekday, nth: nth}
 	return
 }
 
 func (cf cronField) handleInvalidExpr(bounds bound, initBounds int) (err error) {
 	if cf.min == initBounds || cf.max == initBounds {
-		err = errors.New("invalid cron field")
+		err = ErrInvalidField
 		return
 	}
 
-	if cf.min < bounds.min || cf.max > bounds.max {
-		err = errors.New("invalid value, out of bounds")
+	if cf.min < bounds.min || cf.min > bounds.max || cf.max < bounds.min || cf.max > bounds.max {
+		err = ErrOutOfBounds
 		return
 	}
 
-	if cf.min > cf.max {
-		err = errors.New("invalid bounds")
+	if cf.min > cf.max && !cf.wrap {
+		err = ErrInvalidRange
 		return
 	}
 
 	if cf.interval != 1 {
 		_range := bounds.max - bounds.min + 1
-		if cf.interval > _range || cf.interval == 0 {
-			err = errors.New("invalid interval")
+		//a step as long as the field or longer, as in "*/60" or "1/60" for
+		//minutes, only ever matches its start, and is taken for a mistake
+		if cf.interval >= _range || cf.interval == 0 {
+			err = ErrInvalidStep
 			return
 		}
 	}
@@ -92,13 +424,54 @@ func (cf cronField) handleInvalidExpr(bounds bound, initBounds int) (err error)
 	return nil
 }
 
-func formatBound(expr string, abbrMap map[string]string) (val int, err error) {
-	//handleabbreviations
-	abbrVal, ok := abbrMap[strings.ToUpper(expr)]
-	if !ok {
-		abbrVal = expr
+// maxValue is the highest value a field takes as written, which for day of week
+// is 7, the Sunday alias that handleSundayAlias resolves.
+func maxValue(bounds bound) int {
+	if bounds == DOWBound {
+		return SUNDAY_ALIAS
+	}
+
+	return bounds.max
+}
+
+func formatDayOfWeek(tok token, abbrMap map[string]string) (weekday int, err error) {
+	weekday, err = formatValue(tok, abbrMap)
+	if err != nil {
+		return
+	}
+
+	if weekday == SUNDAY_ALIAS {
+		weekday = DOWBound.min
+	}
+
+	if weekday < DOWBound.min || weekday > DOWBound.max {
+		err = ErrOutOfBounds
+	}
+
+	return
+}
+
+// formatValue returns the number a number or name token stands for, looking
+// names up in abbrMap.
+func formatValue(tok token, abbrMap map[string]string) (val int, err error) {
+	switch tok.kind {
+	case tokenNumber:
+		val, err = strconv.Atoi(tok.text)
+	case tokenName:
+		//handleabbreviations
+		abbrVal, ok := abbrMap[strings.ToUpper(tok.text)]
+		if !ok {
+			return 0, &tokenError{tok: tok, err: ErrInvalidValue}
+		}
+
+		val, err = strconv.Atoi(abbrVal)
+	default:
+		return 0, &tokenError{tok: tok, err: ErrUnexpectedToken}
+	}
+
+	if err != nil {
+		err = &tokenError{tok: tok, err: ErrInvalidValue}
 	}
 
-	val, err = strconv.Atoi(abbrVal)
 	return
 }
@@ -1,6 +1,8 @@
 package cronparser
 
 import (
+	"math/rand"
+	"strconv"
 	"testing"
 )
 
@@ -21,7 +23,7 @@ func TestSlashHandler(t *testing.T) {
 
 	for _, tc := range slashIntervalTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleSlash()
 			assertSuccess(t, cf.interval, tc.expected, err)
 		})
@@ -43,9 +45,9 @@ func TestSlashHandler(t *testing.T) {
 
 	for _, tc := range slashExprTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleSlash()
-			assertSuccess(t, cf.expr, tc.expected, err)
+			assertSuccess(t, cf.expr(), tc.expected, err)
 		})
 	}
 }
@@ -68,9 +70,9 @@ func TestAsteriskHandler(t *testing.T) {
 
 	for _, tc := range asteriskTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleAsterisk(tc.bounds)
-			assertSuccess(t, cf.expr, tc.expected, err)
+			assertSuccess(t, cf.expr(), tc.expected, err)
 		})
 	}
 }
@@ -85,7 +87,6 @@ func TestSingleValueHandler(t *testing.T) {
 		{name: "particular instant", expr: "0", bounds: HourBound, expected: "0-0"},
 		{name: "invalid particular instant", expr: "55", bounds: DOMBound, expected: "55-55"},
 		{name: "invalid special char", expr: "?", bounds: MinuteBound, expected: "?-?"},
-		{name: "regular instants", expr: "*/44", expected: "*/44-*/44"},
 		{name: "bounded regular instants", expr: "1-5/0", expected: "1-5/0"},
 		{name: "multiple abbreviation", expr: "Mon-Fri", expected: "Mon-Fri"},
 		{name: "single abbreviation", expr: "Janu", expected: "Janu-Janu"},
@@ -93,9 +94,50 @@ func TestSingleValueHandler(t *testing.T) {
 
 	for _, tc := range svTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
-			err := cf.handleSingleValue()
-			assertSuccess(t, cf.expr, tc.expected, err)
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleSingleValue(tc.bounds)
+			assertSuccess(t, cf.expr(), tc.expected, err)
+		})
+	}
+
+	svFailureTestCases := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{name: "regular instants", expr: "*/44", expected: "unexpected token"},
+		{name: "empty", expr: "", expected: "invalid cron field"},
+	}
+
+	for _, tc := range svFailureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleSingleValue(MinuteBound)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	steppedTestCases := []struct {
+		name     string
+		expr     string
+		bounds   bound
+		expected string
+	}{
+		{name: "stepped instant", expr: "5/15", bounds: MinuteBound, expected: "5-59"},
+		{name: "stepped abbreviation", expr: "MON/2", bounds: DOWBound, expected: "MON-6"},
+		{name: "stepped range", expr: "1-5/2", bounds: DOMBound, expected: "1-5"},
+	}
+
+	for _, tc := range steppedTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleSlash()
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			err = cf.handleSingleValue(tc.bounds)
+			assertSuccess(t, cf.expr(), tc.expected, err)
 		})
 	}
 }
@@ -107,14 +149,17 @@ func TestHyphenHandler(t *testing.T) {
 		abbr     map[string]string
 		expected string
 	}{
-		{name: "invalid special char", expr: "?-?", abbr: map[string]string{}, expected: "strconv.Atoi: parsing \"?\": invalid syntax"},
-		{name: "invalid abbreviation", expr: "Janu-Janu", abbr: MONTH_ABBREVIATIONS, expected: "strconv.Atoi: parsing \"Janu\": invalid syntax"},
-		{name: "with interval", expr: "1-5/0", abbr: DOW_ABBREVIATIONS, expected: "strconv.Atoi: parsing \"5/0\": invalid syntax"},
+		{name: "invalid special char", expr: "?-?", abbr: map[string]string{}, expected: "unexpected token"},
+		{name: "invalid abbreviation", expr: "Janu-Janu", abbr: MONTH_ABBREVIATIONS, expected: "invalid value"},
+		{name: "with interval", expr: "1-5/0", abbr: DOW_ABBREVIATIONS, expected: "unexpected token"},
+		{name: "missing start", expr: "-5", abbr: map[string]string{}, expected: "unexpected token"},
+		{name: "missing end", expr: "5-", abbr: map[string]string{}, expected: "unexpected token"},
+		{name: "two hyphens", expr: "1-2-3", abbr: map[string]string{}, expected: "unexpected token"},
 	}
 
 	for _, tc := range hyphenFailureTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleHyphen(tc.abbr)
 			assertError(t, err, tc.expected)
 		})
@@ -134,7 +179,7 @@ func TestHyphenHandler(t *testing.T) {
 
 	for _, tc := range hyphenMaxBoundTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleHyphen(tc.abbr)
 			assertSuccess(t, cf.max, tc.expected, err)
 		})
@@ -154,7 +199,7 @@ func TestHyphenHandler(t *testing.T) {
 
 	for _, tc := range hyphenMinBoundTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cf := NewCronField(tc.expr)
+			cf := NewCronField(lexTerm(tc.expr))
 			err := cf.handleHyphen(tc.abbr)
 			assertSuccess(t, cf.min, tc.expected, err)
 		})
@@ -164,7 +209,7 @@ func TestHyphenHandler(t *testing.T) {
 func TestInvalidExprHandler(t *testing.T) {
 	t.Run("invalid max interval", func(t *testing.T) {
 		expr := "1-5/44"
-		cf := NewCronField(expr)
+		cf := NewCronField(lexTerm(expr))
 		cf.interval = 44 ////from TestSlashHandler.bounded_regular_instant
 		cf.min = 1
 		cf.max = 5
@@ -175,7 +220,7 @@ func TestInvalidExprHandler(t *testing.T) {
 
 	t.Run("invalid min interval", func(t *testing.T) {
 		expr := "*/0"
-		cf := NewCronField(expr)
+		cf := NewCronField(lexTerm(expr))
 		cf.interval = 0 ////from TestSlashHandler.bounded_regular_instant
 		cf.min = 0
 		cf.max = 6
@@ -186,7 +231,7 @@ func TestInvalidExprHandler(t *testing.T) {
 
 	t.Run("invalid Max bound", func(t *testing.T) {
 		expr := "0-55"
-		cf := NewCronField(expr)
+		cf := NewCronField(lexTerm(expr))
 		cf.max = 55 //from hyphenMaxBoundTestCases.bounded_instants
 		cf.min = 0
 		err := cf.handleInvalidExpr(DOMBound, FRInitBounds)
@@ -196,7 +241,7 @@ func TestInvalidExprHandler(t *testing.T) {
 
 	t.Run("invalid Min bound", func(t *testing.T) {
 		expr := "0-55"
-		cf := NewCronField(expr)
+		cf := NewCronField(lexTerm(expr))
 		cf.min = 0 //from hyphenMinBoundTestCases.bounded_instants
 		cf.max = 55
 		err := cf.handleInvalidExpr(DOMBound, FRInitBounds)
@@ -206,7 +251,7 @@ func TestInvalidExprHandler(t *testing.T) {
 
 	t.Run("invalid abbr bounds", func(t *testing.T) {
 		expr := "Fri-Mon"
-		cf := NewCronField(expr) //from hyphenMinBoundTestCases.bounded_instants
+		cf := NewCronField(lexTerm(expr)) //from hyphenMinBoundTestCases.bounded_instants
 		cf.interval = 1
 		cf.min = 5
 		cf.max = 1
@@ -215,9 +260,21 @@ func TestInvalidExprHandler(t *testing.T) {
 		assertError(t, err, expected)
 	})
 
+	t.Run("valid wrapped abbr bounds", func(t *testing.T) {
+		cf := NewCronField(lexTerm("Fri-Mon"))
+		cf.interval = 1
+		cf.min = 5
+		cf.max = 1
+		cf.wrap = true
+		err := cf.handleInvalidExpr(DOWBound, FRInitBounds)
+		if err != nil {
+			t.Fatal("error is not expected here: ", err)
+		}
+	})
+
 	t.Run("valid single Value", func(t *testing.T) {
 		expr := "2"
-		cf := NewCronField(expr)
+		cf := NewCronField(lexTerm(expr))
 		cf.interval = 1
 		cf.min = 2
 		cf.max = 2
@@ -235,13 +292,14 @@ func TestBoundFormat(t *testing.T) {
 		abbr     map[string]string
 		expected string
 	}{
-		{name: "abbreviation", val: "january", abbr: MONTH_ABBREVIATIONS, expected: "strconv.Atoi: parsing \"january\": invalid syntax"},
-		{name: "abbreviation", val: "L", abbr: map[string]string{}, expected: "strconv.Atoi: parsing \"L\": invalid syntax"},
+		{name: "abbreviation", val: "janu", abbr: MONTH_ABBREVIATIONS, expected: "invalid value"},
+		{name: "abbreviation", val: "L", abbr: map[string]string{}, expected: "invalid value"},
+		{name: "not a value", val: "*", abbr: map[string]string{}, expected: "unexpected token"},
 	}
 
 	for _, tc := range failureTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := formatBound(tc.val, tc.abbr)
+			_, err := formatValue(lexTerm(tc.val)[0], tc.abbr)
 			assertError(t, err, tc.expected)
 		})
 	}
@@ -253,13 +311,226 @@ func TestBoundFormat(t *testing.T) {
 		expected int
 	}{
 		{name: "abbreviation", val: "jan", abbr: MONTH_ABBREVIATIONS, expected: 1},
+		{name: "full name", val: "january", abbr: MONTH_ABBREVIATIONS, expected: 1},
+		{name: "full name", val: "Saturday", abbr: DOW_ABBREVIATIONS, expected: 6},
 		{name: "abbreviation", val: "2134", abbr: map[string]string{}, expected: 2134},
 	}
 
 	for _, tc := range successTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := formatBound(tc.val, tc.abbr)
+			got, err := formatValue(lexTerm(tc.val)[0], tc.abbr)
 			assertSuccess(t, got, tc.expected, err)
 		})
 	}
 }
+
+func TestSundayAliasHandler(t *testing.T) {
+	sundayTestCases := []struct {
+		name           string
+		min, max       int
+		interval       int
+		bounds         bound
+		expectedMin    int
+		expectedMax    int
+		expectedSunday bool
+	}{
+		{name: "seven", min: 7, max: 7, interval: 1, bounds: DOWBound, expectedMin: 0, expectedMax: 0, expectedSunday: false},
+		{name: "range to seven", min: 5, max: 7, interval: 1, bounds: DOWBound, expectedMin: 5, expectedMax: 6, expectedSunday: true},
+		{name: "stepped past seven", min: 4, max: 7, interval: 2, bounds: DOWBound, expectedMin: 4, expectedMax: 6, expectedSunday: false},
+		{name: "whole week", min: 0, max: 7, interval: 1, bounds: DOWBound, expectedMin: 0, expectedMax: 6, expectedSunday: false},
+		{name: "not day of week", min: 5, max: 7, interval: 1, bounds: MonthBound, expectedMin: 5, expectedMax: 7, expectedSunday: false},
+	}
+
+	for _, tc := range sundayTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(""))
+			cf.min, cf.max, cf.interval = tc.min, tc.max, tc.interval
+			err := cf.handleSundayAlias(tc.bounds)
+			assertSuccess(t, []int{cf.min, cf.max}, []int{tc.expectedMin, tc.expectedMax}, err)
+			assertSuccess(t, cf.sunday, tc.expectedSunday, err)
+		})
+	}
+
+	t.Run("descending from seven", func(t *testing.T) {
+		cf := NewCronField(lexTerm(""))
+		cf.min, cf.max, cf.interval = 7, 2, 1
+		err := cf.handleSundayAlias(DOWBound)
+		assertError(t, err, ErrInvalidRange.Error())
+	})
+
+	t.Run("descending from seven wraps around", func(t *testing.T) {
+		cf := NewCronField(lexTerm(""))
+		cf.min, cf.max, cf.interval, cf.wrap = 7, 2, 1, true
+		err := cf.handleSundayAlias(DOWBound)
+		assertSuccess(t, []int{cf.min, cf.max}, []int{0, 2}, err)
+	})
+
+	t.Run("stepped from seven", func(t *testing.T) {
+		cf := NewCronField(lexTerm(""))
+		cf.min, cf.max, cf.interval, cf.stepped = 7, 6, 2, true
+		err := cf.handleSundayAlias(DOWBound)
+		assertSuccess(t, []int{cf.min, cf.max}, []int{0, 6}, err)
+	})
+}
+
+func TestLastHandler(t *testing.T) {
+	lastTestCases := []struct {
+		name     string
+		expr     string
+		field    FieldKind
+		expected *daySpec
+	}{
+		{name: "last day", expr: "L", field: FieldDayOfMonth, expected: &daySpec{kind: specLastDay}},
+		{name: "days before last day", expr: "L-2", field: FieldDayOfMonth, expected: &daySpec{kind: specLastDay, value: 2}},
+		{name: "last weekday", expr: "LW", field: FieldDayOfMonth, expected: &daySpec{kind: specLastWeekday}},
+		{name: "last of weekday", expr: "SATL", field: FieldDayOfWeek, expected: &daySpec{kind: specLastOfWeekday, value: 6}},
+		{name: "not in month", expr: "L", field: FieldMonth, expected: nil},
+		{name: "plain value", expr: "5", field: FieldDayOfWeek, expected: nil},
+	}
+
+	for _, tc := range lastTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleLast(tc.field, tc.field.abbreviations())
+			assertSuccess(t, cf.spec, tc.expected, err)
+		})
+	}
+}
+
+func TestNearestWeekdayHandler(t *testing.T) {
+	t.Run("nearest weekday", func(t *testing.T) {
+		cf := NewCronField(lexTerm("1W"))
+		err := cf.handleNearestWeekday(FieldDayOfMonth)
+		assertSuccess(t, cf.spec, &daySpec{kind: specNearestWeekday, value: 1}, err)
+	})
+
+	t.Run("invalid day", func(t *testing.T) {
+		cf := NewCronField(lexTerm("0W"))
+		err := cf.handleNearestWeekday(FieldDayOfMonth)
+		assertError(t, err, "invalid value, out of bounds")
+	})
+}
+
+func TestNthHandler(t *testing.T) {
+	t.Run("nth weekday", func(t *testing.T) {
+		cf := NewCronField(lexTerm("1#2"))
+		err := cf.handleNth(FieldDayOfWeek, DOW_ABBREVIATIONS)
+		assertSuccess(t, cf.spec, &daySpec{kind: specNthOfWeekday, value: 1, nth: 2}, err)
+	})
+
+	t.Run("invalid nth", func(t *testing.T) {
+		cf := NewCronField(lexTerm("MON#x"))
+		err := cf.handleNth(FieldDayOfWeek, DOW_ABBREVIATIONS)
+		assertError(t, err, "invalid value")
+	})
+}
+
+func TestHashHandler(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		expr     string
+		hash     bool
+		expected string
+	}{
+		{name: "no hash key", expr: "H", hash: false, expected: "H needs a hash key"},
+		{name: "unclosed range", expr: "H(0-29", hash: true, expected: "invalid cron field"},
+		{name: "range out of bounds", expr: "H(0-60)", hash: true, expected: "invalid value, out of bounds"},
+		{name: "reversed range", expr: "H(30-10)", hash: true, expected: "invalid bounds"},
+		{name: "zero interval", expr: "H/0", hash: true, expected: "invalid interval"},
+		{name: "trailing chars", expr: "H5", hash: true, expected: "invalid cron field"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := newConfig(nil)
+			if tc.hash {
+				cfg = newConfig([]Option{WithHashKey("job")})
+			}
+
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleHash(MinuteBound, cfg.hash)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		expr     string
+		bounds   bound
+		expected string
+	}{
+		{name: "not hashed", expr: "*/15", bounds: MinuteBound, expected: "*/15"},
+		{name: "hashed value", expr: "H", bounds: MinuteBound, expected: "54"},
+		{name: "hashed start", expr: "H/15", bounds: MinuteBound, expected: "9-59/15"},
+		{name: "hashed value in range", expr: "H(0-29)", bounds: MinuteBound, expected: "24"},
+		{name: "hashed start in range", expr: "H(10-19)/4", bounds: MinuteBound, expected: "12-19/4"},
+		{name: "hashed day of month", expr: "H", bounds: DOMBound, expected: "15"},
+		{name: "hashed day of month start", expr: "H/7", bounds: DOMBound, expected: "1-28/7"},
+		{name: "hashed day of month in range", expr: "H(29-31)", bounds: DOMBound, expected: "29"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleHash(tc.bounds, newConfig([]Option{WithHashKey("job")}).hash)
+			assertSuccess(t, cf.expr(), tc.expected, err)
+		})
+	}
+
+	t.Run("day of month every month has", func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			cf := NewCronField(lexTerm("H"))
+			err := cf.handleHash(DOMBound, newConfig([]Option{WithHashKey("job" + strconv.Itoa(i))}).hash)
+			if err != nil {
+				t.Fatalf("key job%d: %v", i, err)
+			}
+
+			if day, _ := strconv.Atoi(cf.expr()); day > HASH_DOM_MAX {
+				t.Errorf("key job%d: got day %d, want at most %d", i, day, HASH_DOM_MAX)
+			}
+		}
+	})
+}
+
+func TestRandomHandler(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{name: "too many tildes", expr: "1~5~9", expected: "invalid cron field"},
+		{name: "out of bounds", expr: "0~8", expected: "invalid value, out of bounds"},
+		{name: "reversed range", expr: "5~1", expected: "invalid bounds"},
+		{name: "invalid value", expr: "1~x", expected: "invalid value"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleRandom(DOWBound, DOW_ABBREVIATIONS, newConfig([]Option{WithRand(rand.New(rand.NewSource(1)))}).random)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{name: "not random", expr: "1-5", expected: "1-5"},
+		{name: "random value", expr: "~", expected: "6"},
+		{name: "random value in range", expr: "1~3", expected: "3"},
+		{name: "open start", expr: "~2", expected: "2"},
+		{name: "open end", expr: "5~", expected: "6"},
+		{name: "abbreviations", expr: "MON~FRI", expected: "2"},
+		{name: "single value range", expr: "3~3", expected: "3"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cf := NewCronField(lexTerm(tc.expr))
+			err := cf.handleRandom(DOWBound, DOW_ABBREVIATIONS, newConfig([]Option{WithRand(rand.New(rand.NewSource(1)))}).random)
+			assertSuccess(t, cf.expr(), tc.expected, err)
+		})
+	}
+}
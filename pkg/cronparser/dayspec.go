@@ -0,0 +1,122 @@
+package cronparser
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// daySpecKind lists the Quartz-style day values whose days depend on the month.
+type daySpecKind int
+
+const (
+	specLastDay        daySpecKind = iota //L or L-n in day of month
+	specLastWeekday                       //LW in day of month
+	specNearestWeekday                    //nW in day of month
+	specLastOfWeekday                     //nL in day of week
+	specNthOfWeekday                      //n#k in day of week
+)
+
+// daySpec is a day field value that can't be expanded up front by
+// buildIntList; resolve turns it into days once the month is known.
+type daySpec struct {
+	kind  daySpecKind
+	value int //days before the last, day of month or day of week
+	nth   int //occurrence of the day of week, for specNthOfWeekday
+}
+
+func (ds daySpec) String() string {
+	switch ds.kind {
+	case specLastDay:
+		if ds.value == 0 {
+			return "L"
+		}
+
+		return "L-" + strconv.Itoa(ds.value)
+	case specLastWeekday:
+		return "LW"
+	case specNearestWeekday:
+		return strconv.Itoa(ds.value) + "W"
+	case specLastOfWeekday:
+		return strconv.Itoa(ds.value) + "L"
+	}
+
+	return strconv.Itoa(ds.value) + "#" + strconv.Itoa(ds.nth)
+}
+
+// resolve returns the day of month ds stands for in the given month, if any.
+func (ds daySpec) resolve(year int, month time.Month) (int, bool) {
+	lastDay := daysIn(year, month)
+	weekdayOf := func(day int) time.Weekday {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+	}
+
+	switch ds.kind {
+	case specLastDay:
+		day := lastDay - ds.value
+		return day, day >= 1
+	case specLastWeekday:
+		day := lastDay
+		for weekdayOf(day) == time.Saturday || weekdayOf(day) == time.Sunday {
+			day--
+		}
+
+		return day, true
+	case specNearestWeekday:
+		return nearestWeekday(ds.value, lastDay, weekdayOf(ds.value))
+	case specLastOfWeekday:
+		day := lastDay - (int(weekdayOf(lastDay))-ds.value+7)%7
+		return day, true
+	}
+
+	day := 1 + (ds.value-int(weekdayOf(1))+7)%7 + 7*(ds.nth-1)
+	return day, day <= lastDay
+}
+
+// nearestWeekday moves a weekend day to the closest weekday without leaving
+// the month, as Quartz does for nW.
+func nearestWeekday(day, lastDay int, weekday time.Weekday) (int, bool) {
+	if day > lastDay {
+		return 0, false
+	}
+
+	switch weekday {
+	case time.Saturday:
+		if day == 1 {
+			return day + 2, true
+		}
+
+		return day - 1, true
+	case time.Sunday:
+		if day == lastDay {
+			return day - 2, true
+		}
+
+		return day + 1, true
+	}
+
+	return day, true
+}
+
+// resolveDaySpecs returns the sorted days of the month the specs stand for.
+func resolveDaySpecs(specs []daySpec, year int, month time.Month) []int {
+	var days []int
+	for _, spec := range specs {
+		if day, ok := spec.resolve(year, month); ok {
+			days = append(days, day)
+		}
+	}
+
+	sort.Ints(days)
+	return days
+}
+
+func daySpecsJoin(specs []daySpec, sep string) string {
+	strSpecs := make([]string, len(specs))
+	for i, spec := range specs {
+		strSpecs[i] = spec.String()
+	}
+
+	return strings.Join(strSpecs, sep)
+}
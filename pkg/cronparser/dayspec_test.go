@@ -0,0 +1,99 @@
+package cronparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaySpecResolve(t *testing.T) {
+	resolveTestCases := []struct {
+		name     string
+		spec     daySpec
+		year     int
+		month    time.Month
+		expected int
+		ok       bool
+	}{
+		{name: "last day", spec: daySpec{kind: specLastDay}, year: 2024, month: time.February, expected: 29, ok: true},
+		{name: "days before last day", spec: daySpec{kind: specLastDay, value: 3}, year: 2023, month: time.February, expected: 25, ok: true},
+		{name: "last weekday on a sunday", spec: daySpec{kind: specLastWeekday}, year: 2024, month: time.March, expected: 29, ok: true},
+		{name: "nearest weekday to a saturday", spec: daySpec{kind: specNearestWeekday, value: 15}, year: 2024, month: time.June, expected: 14, ok: true},
+		{name: "nearest weekday to a sunday", spec: daySpec{kind: specNearestWeekday, value: 16}, year: 2024, month: time.June, expected: 17, ok: true},
+		{name: "nearest weekday stays in month", spec: daySpec{kind: specNearestWeekday, value: 1}, year: 2024, month: time.June, expected: 3, ok: true},
+		{name: "nearest weekday stays in month at the end", spec: daySpec{kind: specNearestWeekday, value: 30}, year: 2024, month: time.June, expected: 28, ok: true},
+		{name: "nearest weekday past month end", spec: daySpec{kind: specNearestWeekday, value: 31}, year: 2024, month: time.June, ok: false},
+		{name: "last friday", spec: daySpec{kind: specLastOfWeekday, value: 5}, year: 2024, month: time.May, expected: 31, ok: true},
+		{name: "last sunday", spec: daySpec{kind: specLastOfWeekday, value: 0}, year: 2024, month: time.May, expected: 26, ok: true},
+		{name: "third friday", spec: daySpec{kind: specNthOfWeekday, value: 5, nth: 3}, year: 2024, month: time.May, expected: 17, ok: true},
+		{name: "fifth monday missing", spec: daySpec{kind: specNthOfWeekday, value: 1, nth: 5}, year: 2024, month: time.May, ok: false},
+	}
+
+	for _, tc := range resolveTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.spec.resolve(tc.year, tc.month)
+			assertSuccess(t, ok, tc.ok, nil)
+			if ok {
+				assertSuccess(t, got, tc.expected, nil)
+			}
+		})
+	}
+}
+
+func TestDaySpecString(t *testing.T) {
+	specs := []daySpec{
+		{kind: specLastDay},
+		{kind: specLastDay, value: 2},
+		{kind: specLastWeekday},
+		{kind: specNearestWeekday, value: 15},
+		{kind: specLastOfWeekday, value: 5},
+		{kind: specNthOfWeekday, value: 5, nth: 3},
+	}
+
+	assertSuccess(t, daySpecsJoin(specs, " "), "L L-2 LW 15W 5L 5#3", nil)
+}
+
+func TestQuartzSchedules(t *testing.T) {
+	from := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	nextTestCases := []struct {
+		name     string
+		cronExpr string
+		expected []time.Time
+	}{
+		{name: "third friday", cronExpr: "0 12 ? * 5#3 cmd", expected: []time.Time{
+			time.Date(2024, time.May, 17, 12, 0, 0, 0, time.UTC),
+			time.Date(2024, time.June, 21, 12, 0, 0, 0, time.UTC),
+		}},
+		{name: "last weekday", cronExpr: "0 0 LW * ? cmd", expected: []time.Time{
+			time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.June, 28, 0, 0, 0, 0, time.UTC),
+		}},
+		{name: "first or last day", cronExpr: "0 0 1,L * * cmd", expected: []time.Time{
+			time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{name: "last friday or the 15th", cronExpr: "0 0 15 * FRIL cmd", expected: []time.Time{
+			time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.May, 31, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tc := range nextTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr)
+			assertSuccess(t, schedule.Between(from, from.AddDate(0, 2, 0), len(tc.expected)), tc.expected, err)
+		})
+	}
+
+	t.Run("matches", func(t *testing.T) {
+		schedule, err := Parse("0 0 L * ? cmd")
+		assertSuccess(t, schedule.Matches(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)), true, err)
+		assertSuccess(t, schedule.Matches(time.Date(2024, time.February, 28, 0, 0, 0, 0, time.UTC)), false, err)
+	})
+
+	t.Run("prev", func(t *testing.T) {
+		schedule, err := Parse("0 0 ? * 1#5 cmd")
+		assertSuccess(t, schedule.Prev(from), time.Date(2024, time.April, 29, 0, 0, 0, 0, time.UTC), err)
+	})
+}
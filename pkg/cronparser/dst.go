@@ -0,0 +1,135 @@
+package cronparser
+
+import (
+	"sort"
+	"time"
+)
+
+// DSTPolicy decides what happens to wall clock times that a daylight saving
+// transition skips (spring forward) or repeats (fall back).
+//
+// As in Vixie cron, entries whose minute or hour field starts with an asterisk
+// just follow the wall clock whatever the policy: they don't catch up on
+// skipped times and fire on both occurrences of a repeated time.
+type DSTPolicy int
+
+const (
+	// DSTRunOnce runs a skipped time once, at the moment the clocks jump
+	// forward, and a repeated time only on its first occurrence.
+	// It matches Vixie cron and cronie, and is the default.
+	DSTRunOnce DSTPolicy = iota
+
+	// DSTSkip never runs skipped times, and runs a repeated time only on its
+	// first occurrence.
+	DSTSkip
+
+	// DSTRunTwice runs a skipped time once, at the moment the clocks jump
+	// forward, and a repeated time on both of its occurrences.
+	DSTRunTwice
+)
+
+// transitionWindow is how far around a wall clock time the location is probed
+// for the offsets in use.
+const transitionWindow = 12 * time.Hour
+
+// activations returns the instants, in order, at which the matching wall clock
+// time fires under the schedule's DST policy.
+func (s Schedule) activations(wall time.Time) []time.Time {
+	instants, jump := occurrences(wall, s.loc())
+
+	wildcardTime := s.IsWildcard(FieldMinute) || s.IsWildcard(FieldHour)
+
+	switch {
+	case len(instants) == 0:
+		if wildcardTime || s.dstPolicy == DSTSkip {
+			return nil
+		}
+
+		return []time.Time{jump}
+	case len(instants) > 1 && !wildcardTime && s.dstPolicy != DSTRunTwice:
+		return instants[:1]
+	}
+
+	return instants
+}
+
+// occurrences returns the instants, in order, at which the clocks in loc read
+// wall: one normally, two when a transition repeats it and none when a
+// transition skips it. For a skipped wall clock time it also returns jump, the
+// instant the clocks moved past it.
+func occurrences(wall time.Time, loc *time.Location) (instants []time.Time, jump time.Time) {
+	year, month, day := wall.Date()
+	guess := time.Date(year, month, day, wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+	probes := []time.Time{guess.Add(-transitionWindow), guess, guess.Add(transitionWindow)}
+
+	if sameOffset(probes) {
+		return []time.Time{guess}, jump
+	}
+
+	for _, probe := range probes {
+		_, offset := probe.Zone()
+		instant := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if civil(instant).Equal(wall) && !containsTime(instants, instant) {
+			instants = append(instants, instant)
+		}
+	}
+
+	sort.Slice(instants, func(i, j int) bool {
+		return instants[i].Before(instants[j])
+	})
+
+	if len(instants) == 0 {
+		jump = jumpPast(wall, guess.Add(-transitionWindow), guess.Add(transitionWindow))
+	}
+
+	return instants, jump
+}
+
+func sameOffset(times []time.Time) bool {
+	_, offset := times[0].Zone()
+	for _, t := range times[1:] {
+		if _, other := t.Zone(); other != offset {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jumpPast searches (lo, hi] for the first instant whose wall clock reads
+// later than wall, i.e. the transition that skipped it.
+func jumpPast(wall, lo, hi time.Time) time.Time {
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+		if civil(mid).Before(wall) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return hi
+}
+
+// repeatedSpan returns how far the wall clock search has to start away from
+// from, so that it also covers the other occurrence of a wall clock time that
+// a nearby transition repeats.
+func repeatedSpan(from time.Time, forward bool) time.Duration {
+	_, offset := from.Zone()
+
+	if forward {
+		_, later := from.Add(transitionWindow).Zone()
+		if later < offset {
+			return -time.Duration(offset-later) * time.Second
+		}
+
+		return 0
+	}
+
+	_, earlier := from.Add(-transitionWindow).Zone()
+	if earlier > offset {
+		return time.Duration(earlier-offset) * time.Second
+	}
+
+	return 0
+}
@@ -0,0 +1,142 @@
+package cronparser
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestDSTTransitions(t *testing.T) {
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	nextTestCases := []struct {
+		name     string
+		cronExpr string
+		policy   DSTPolicy
+		from     time.Time
+		expected time.Time
+	}{
+		// America/New_York: 02:00 EST -> 03:00 EDT on Mar 10, 02:00 EDT -> 01:00 EST on Nov 3
+		{name: "New York skipped 02:30 runs once at jump", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.March, 10, 5, 0), expected: utc(time.March, 10, 7, 0)},
+		{name: "New York skipped 02:30 after jump", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.March, 10, 7, 0), expected: utc(time.March, 11, 6, 30)},
+		{name: "New York skipped 02:30 skipped", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTSkip, from: utc(time.March, 10, 5, 0), expected: utc(time.March, 11, 6, 30)},
+		{name: "New York skipped 02:30 run twice", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTRunTwice, from: utc(time.March, 10, 5, 0), expected: utc(time.March, 10, 7, 0)},
+		{name: "New York wildcard hour does not catch up", cronExpr: "CRON_TZ=America/New_York 30 * * * * cmd", policy: DSTRunOnce, from: utc(time.March, 10, 6, 30), expected: utc(time.March, 10, 7, 30)},
+		{name: "New York repeated 01:30 first", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunOnce, from: utc(time.November, 3, 4, 0), expected: utc(time.November, 3, 5, 30)},
+		{name: "New York repeated 01:30 once", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunOnce, from: utc(time.November, 3, 5, 30), expected: utc(time.November, 4, 6, 30)},
+		{name: "New York repeated 01:30 twice", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunTwice, from: utc(time.November, 3, 5, 30), expected: utc(time.November, 3, 6, 30)},
+		{name: "New York repeated 01:15 twice from later wall time", cronExpr: "CRON_TZ=America/New_York 15 1 * * * cmd", policy: DSTRunTwice, from: utc(time.November, 3, 5, 45), expected: utc(time.November, 3, 6, 15)},
+		{name: "New York wildcard hour repeats", cronExpr: "CRON_TZ=America/New_York 30 * * * * cmd", policy: DSTRunOnce, from: utc(time.November, 3, 5, 30), expected: utc(time.November, 3, 6, 30)},
+
+		// Europe/Berlin: 02:00 CET -> 03:00 CEST on Mar 31, 03:00 CEST -> 02:00 CET on Oct 27
+		{name: "Berlin skipped 02:30 runs once at jump", cronExpr: "CRON_TZ=Europe/Berlin 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.March, 30, 23, 0), expected: utc(time.March, 31, 1, 0)},
+		{name: "Berlin skipped 02:30 skipped", cronExpr: "CRON_TZ=Europe/Berlin 30 2 * * * cmd", policy: DSTSkip, from: utc(time.March, 30, 23, 0), expected: utc(time.April, 1, 0, 30)},
+		{name: "Berlin repeated 02:30 once", cronExpr: "CRON_TZ=Europe/Berlin 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.October, 27, 0, 30), expected: utc(time.October, 28, 1, 30)},
+		{name: "Berlin repeated 02:30 twice", cronExpr: "CRON_TZ=Europe/Berlin 30 2 * * * cmd", policy: DSTRunTwice, from: utc(time.October, 27, 0, 30), expected: utc(time.October, 27, 1, 30)},
+
+		// Australia/Sydney: 03:00 AEDT -> 02:00 AEST on Apr 7, 02:00 AEST -> 03:00 AEDT on Oct 6
+		{name: "Sydney repeated 02:30 once", cronExpr: "CRON_TZ=Australia/Sydney 30 2 * * * cmd", policy: DSTSkip, from: utc(time.April, 6, 15, 30), expected: utc(time.April, 7, 16, 30)},
+		{name: "Sydney repeated 02:30 twice", cronExpr: "CRON_TZ=Australia/Sydney 30 2 * * * cmd", policy: DSTRunTwice, from: utc(time.April, 6, 15, 30), expected: utc(time.April, 6, 16, 30)},
+		{name: "Sydney skipped 02:30 runs once at jump", cronExpr: "CRON_TZ=Australia/Sydney 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.October, 5, 14, 0), expected: utc(time.October, 5, 16, 0)},
+
+		// Australia/Lord_Howe: 02:00 -> 02:30 on Oct 6, so 02:30 itself exists but 02:15 does not
+		{name: "Lord Howe 02:30 unaffected", cronExpr: "CRON_TZ=Australia/Lord_Howe 30 2 * * * cmd", policy: DSTSkip, from: utc(time.October, 5, 14, 0), expected: utc(time.October, 5, 15, 30)},
+		{name: "Lord Howe skipped 02:15 runs once at jump", cronExpr: "CRON_TZ=Australia/Lord_Howe 15 2 * * * cmd", policy: DSTRunOnce, from: utc(time.October, 5, 14, 0), expected: utc(time.October, 5, 15, 30)},
+	}
+
+	for _, tc := range nextTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, WithDSTPolicy(tc.policy))
+			assertSuccess(t, schedule.Next(tc.from).UTC(), tc.expected, err)
+		})
+	}
+
+	prevTestCases := []struct {
+		name     string
+		cronExpr string
+		policy   DSTPolicy
+		from     time.Time
+		expected time.Time
+	}{
+		{name: "New York skipped 02:30 runs once at jump", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTRunOnce, from: utc(time.March, 10, 8, 0), expected: utc(time.March, 10, 7, 0)},
+		{name: "New York skipped 02:30 skipped", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTSkip, from: utc(time.March, 10, 8, 0), expected: utc(time.March, 9, 7, 30)},
+		{name: "New York repeated 01:30 once", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunOnce, from: utc(time.November, 3, 7, 0), expected: utc(time.November, 3, 5, 30)},
+		{name: "New York repeated 01:30 twice", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunTwice, from: utc(time.November, 3, 7, 0), expected: utc(time.November, 3, 6, 30)},
+		{name: "New York repeated 01:45 twice from earlier wall time", cronExpr: "CRON_TZ=America/New_York 45 1 * * * cmd", policy: DSTRunTwice, from: utc(time.November, 3, 6, 15), expected: utc(time.November, 3, 5, 45)},
+	}
+
+	for _, tc := range prevTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, WithDSTPolicy(tc.policy))
+			assertSuccess(t, schedule.Prev(tc.from).UTC(), tc.expected, err)
+		})
+	}
+
+	matchesTestCases := []struct {
+		name     string
+		cronExpr string
+		policy   DSTPolicy
+		at       time.Time
+		expected bool
+	}{
+		{name: "New York jump runs skipped 02:30", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTRunOnce, at: utc(time.March, 10, 7, 0), expected: true},
+		{name: "New York jump skips skipped 02:30", cronExpr: "CRON_TZ=America/New_York 30 2 * * * cmd", policy: DSTSkip, at: utc(time.March, 10, 7, 0), expected: false},
+		{name: "New York first 01:30", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunOnce, at: utc(time.November, 3, 5, 30), expected: true},
+		{name: "New York second 01:30 once", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunOnce, at: utc(time.November, 3, 6, 30), expected: false},
+		{name: "New York second 01:30 twice", cronExpr: "CRON_TZ=America/New_York 30 1 * * * cmd", policy: DSTRunTwice, at: utc(time.November, 3, 6, 30), expected: true},
+		{name: "New York second 01:30 wildcard", cronExpr: "CRON_TZ=America/New_York */30 1 * * * cmd", policy: DSTRunOnce, at: utc(time.November, 3, 6, 30), expected: true},
+	}
+
+	for _, tc := range matchesTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, WithDSTPolicy(tc.policy))
+			assertSuccess(t, schedule.Matches(tc.at), tc.expected, err)
+		})
+	}
+
+	t.Run("Between orders repeated times", func(t *testing.T) {
+		schedule, err := Parse("CRON_TZ=America/New_York 0,30 1 * * * cmd", WithDSTPolicy(DSTRunTwice))
+		got := schedule.Between(utc(time.November, 3, 4, 0), utc(time.November, 3, 8, 0), 0)
+		expected := []time.Time{utc(time.November, 3, 5, 0), utc(time.November, 3, 5, 30), utc(time.November, 3, 6, 0), utc(time.November, 3, 6, 30)}
+		assertSuccess(t, toUTC(got), expected, err)
+	})
+
+	t.Run("Between collapses skipped times", func(t *testing.T) {
+		schedule, err := Parse("CRON_TZ=America/New_York 0,30 2,3 * * * cmd")
+		got := schedule.Between(utc(time.March, 10, 6, 0), utc(time.March, 10, 9, 0), 0)
+		expected := []time.Time{utc(time.March, 10, 7, 0), utc(time.March, 10, 7, 30)}
+		assertSuccess(t, toUTC(got), expected, err)
+	})
+}
+
+func TestOccurrences(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	t.Run("normal", func(t *testing.T) {
+		got, _ := occurrences(time.Date(2024, time.March, 10, 1, 30, 0, 0, time.UTC), newYork)
+		assertSuccess(t, toUTC(got), []time.Time{time.Date(2024, time.March, 10, 6, 30, 0, 0, time.UTC)}, nil)
+	})
+
+	t.Run("skipped", func(t *testing.T) {
+		got, jump := occurrences(time.Date(2024, time.March, 10, 2, 30, 0, 0, time.UTC), newYork)
+		assertSuccess(t, len(got), 0, nil)
+		assertSuccess(t, jump.UTC(), time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC), nil)
+	})
+
+	t.Run("repeated", func(t *testing.T) {
+		got, _ := occurrences(time.Date(2024, time.November, 3, 1, 30, 0, 0, time.UTC), newYork)
+		expected := []time.Time{time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC), time.Date(2024, time.November, 3, 6, 30, 0, 0, time.UTC)}
+		assertSuccess(t, toUTC(got), expected, nil)
+	})
+}
+
+func toUTC(times []time.Time) []time.Time {
+	utc := make([]time.Time, len(times))
+	for i, t := range times {
+		utc[i] = t.UTC()
+	}
+
+	return utc
+}
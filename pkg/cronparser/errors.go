@@ -0,0 +1,165 @@
+package cronparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Causes of a ParseError, for use with errors.Is.
+var (
+	ErrFieldCount       = errors.New("invalid number of cron fields")
+	ErrTimeZone         = errors.New("invalid time zone")
+	ErrMacro            = errors.New("invalid macro")
+	ErrIntervalSchedule = errors.New("interval schedules need ParseScheduler")
+	ErrInvalidDuration  = errors.New("invalid duration")
+	ErrInvalidCharacter = errors.New("invalid character")
+	ErrUnexpectedToken  = errors.New("unexpected token")
+	ErrInvalidField     = errors.New("invalid cron field")
+	ErrInvalidValue     = errors.New("invalid value")
+	ErrOutOfBounds      = errors.New("invalid value, out of bounds")
+	ErrInvalidRange     = errors.New("invalid bounds")
+	ErrInvalidStep      = errors.New("invalid interval")
+	ErrNoHashKey        = errors.New("H needs a hash key")
+)
+
+// ParseError describes why an expression could not be parsed, and where.
+type ParseError struct {
+	Field  string //the time field, e.g. "minute", or "" when the error isn't about one
+	Token  string //the offending text, or "" when there is none, e.g. a missing field
+	Offset int    //byte offset of Token in the expression
+	Err    error  //the cause, one of the Err variables
+
+	//what Token was probably meant to be, e.g. "MON" for "MOM", or "" when
+	//nothing comes close
+	Suggestion string
+}
+
+func (e *ParseError) Error() string {
+	msg := "Validation Error: " + e.Err.Error()
+	if e.Field != "" {
+		msg = "Parsing Error: " + e.Field + " field " + strconv.Quote(e.Token) + ": " + e.Err.Error()
+	}
+
+	if e.Suggestion != "" {
+		msg += " (did you mean " + strconv.Quote(e.Suggestion) + "?)"
+	}
+
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors lists every problem found in an expression parsed WithAllErrors.
+// errors.Is and errors.As look through each of them.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+func (e ParseErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e ParseErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e ParseErrors) has(field FieldKind) bool {
+	for _, err := range e {
+		if err.Field == field.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tokenError is an error found at a single token of a term, such as a stray
+// "-" in "-5", which termError points at.
+type tokenError struct {
+	tok token
+	err error
+}
+
+func (e *tokenError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tokenError) Unwrap() error {
+	return e.err
+}
+
+// termError describes err found in the comma separated term, at offset within
+// its field. A number that failed to parse is pointed at directly.
+func termError(term []token, offset int, err error) *ParseError {
+	var tokErr *tokenError
+	if errors.As(err, &tokErr) {
+		return &ParseError{Token: tokErr.tok.text, Offset: tokErr.tok.offset, Err: tokErr.err}
+	}
+
+	expr := joinTokens(term)
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		return &ParseError{Token: expr, Offset: offset, Err: err}
+	}
+
+	if i := strings.Index(expr, numErr.Num); i != -1 {
+		return &ParseError{Token: numErr.Num, Offset: offset + i, Err: ErrInvalidValue}
+	}
+
+	return &ParseError{Token: expr, Offset: offset, Err: ErrInvalidValue}
+}
+
+// locate moves a ParseError found in part of the expression to where that part
+// starts, and names its field unless field is "".
+func locate(err error, field string, offset int) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	if field != "" {
+		parseErr.Field = field
+	}
+
+	parseErr.Offset += offset
+	return parseErr
+}
+
+// asWritten gives a ParseError the Token found at its Offset in cronExpr, as
+// time fields are upper cased before they are parsed.
+func asWritten(err error, cronExpr string) error {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	end := parseErr.Offset + len(parseErr.Token)
+	if end <= len(cronExpr) && strings.EqualFold(cronExpr[parseErr.Offset:end], parseErr.Token) {
+		parseErr.Token = cronExpr[parseErr.Offset:end]
+	}
+
+	return parseErr
+}
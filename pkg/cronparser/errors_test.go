@@ -0,0 +1,132 @@
+package cronparser
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	errorTestCases := []struct {
+		name     string
+		cronExpr string
+		opts     []Option
+		expected *ParseError
+	}{
+		{name: "empty expression", cronExpr: "", expected: &ParseError{Offset: 0, Err: ErrFieldCount}},
+		{name: "missing command", cronExpr: "* * * * *", expected: &ParseError{Offset: 9, Err: ErrFieldCount}},
+		{name: "unknown time zone", cronExpr: "CRON_TZ=Mars/Base * * * * * cmd", expected: &ParseError{Token: "Mars/Base", Offset: 8, Err: ErrTimeZone}},
+		{name: "macro without command", cronExpr: "@daily", expected: &ParseError{Offset: 6, Err: ErrFieldCount}},
+		{name: "macro without command after time zone", cronExpr: "TZ=UTC @daily", expected: &ParseError{Offset: 13, Err: ErrFieldCount}},
+		{name: "unknown macro", cronExpr: "TZ=UTC @fortnightly cmd", expected: &ParseError{Token: "@fortnightly", Offset: 7, Err: ErrMacro}},
+		{name: "invalid character", cronExpr: "0 0 1-5@ * * cmd", expected: &ParseError{Field: "day of month", Token: "@", Offset: 7, Err: ErrInvalidCharacter}},
+		{name: "invalid value", cronExpr: "0 0 X * * cmd", expected: &ParseError{Field: "day of month", Token: "X", Offset: 4, Err: ErrInvalidValue}},
+		{name: "second step", cronExpr: "*/5/5 * * * * cmd", expected: &ParseError{Field: "minute", Token: "/", Offset: 3, Err: ErrUnexpectedToken}},
+		{name: "missing step", cronExpr: "*/ * * * * cmd", expected: &ParseError{Field: "minute", Token: "/", Offset: 1, Err: ErrUnexpectedToken}},
+		{name: "missing range start", cronExpr: "0 -1 * * * cmd", expected: &ParseError{Field: "hour", Token: "-", Offset: 2, Err: ErrUnexpectedToken}},
+		{name: "empty term", cronExpr: "0 1,,2 * * * cmd", expected: &ParseError{Field: "hour", Token: ",", Offset: 4, Err: ErrUnexpectedToken}},
+		{name: "non-ascii character", cronExpr: "0 0 * * Mé cmd", expected: &ParseError{Field: "day of week", Token: "é", Offset: 9, Err: ErrInvalidCharacter}},
+		{name: "out of bounds", cronExpr: "0  24 * * * cmd", expected: &ParseError{Field: "hour", Token: "24", Offset: 3, Err: ErrOutOfBounds, Suggestion: "0"}},
+		{name: "invalid step", cronExpr: "0 0 1,*/0 * * cmd", expected: &ParseError{Field: "day of month", Token: "*/0", Offset: 6, Err: ErrInvalidStep, Suggestion: "*"}},
+		{name: "invalid range", cronExpr: "0 0 * * Fri-Mon cmd", expected: &ParseError{Field: "day of week", Token: "Fri-Mon", Offset: 8, Err: ErrInvalidRange}},
+		{name: "invalid value", cronExpr: "0 0 * jan-foo * cmd", expected: &ParseError{Field: "month", Token: "foo", Offset: 10, Err: ErrInvalidValue}},
+		{name: "after a time zone", cronExpr: "CRON_TZ=UTC 61 * * * * cmd", expected: &ParseError{Field: "minute", Token: "61", Offset: 12, Err: ErrOutOfBounds}},
+		{name: "seconds", cronExpr: "*/61 * * * * * cmd", opts: []Option{WithSeconds()}, expected: &ParseError{Field: "second", Token: "*/61", Offset: 0, Err: ErrInvalidStep, Suggestion: "0"}},
+		{name: "day spec", cronExpr: "0 0 ? * MON,5#6 cmd", expected: &ParseError{Field: "day of week", Token: "5#6", Offset: 12, Err: ErrOutOfBounds}},
+		{name: "no hash key", cronExpr: "H * * * * cmd", expected: &ParseError{Field: "minute", Token: "H", Offset: 0, Err: ErrNoHashKey}},
+	}
+
+	for _, tc := range errorTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr, tc.opts...)
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected a ParseError, but got %v", err)
+			}
+
+			assertSuccess(t, parseErr, tc.expected, nil)
+			assertSuccess(t, errors.Is(err, tc.expected.Err), true, nil)
+		})
+	}
+
+	t.Run("interval duration", func(t *testing.T) {
+		_, err := ParseScheduler("@every  1ms cmd")
+		assertSuccess(t, err, &ParseError{Token: "1ms", Offset: 8, Err: ErrInvalidDuration}, nil)
+	})
+
+	t.Run("empty interval expression", func(t *testing.T) {
+		_, err := ParseScheduler("")
+		assertSuccess(t, errors.Is(err, ErrFieldCount), true, nil)
+	})
+}
+
+func TestParseErrorString(t *testing.T) {
+	errorStringTestCases := []struct {
+		name     string
+		err      *ParseError
+		expected string
+	}{
+		{name: "whole expression", err: &ParseError{Offset: 9, Err: ErrFieldCount}, expected: "Validation Error: invalid number of cron fields"},
+		{name: "one field", err: &ParseError{Field: "hour", Token: "24", Offset: 2, Err: ErrOutOfBounds}, expected: "Parsing Error: hour field \"24\": invalid value, out of bounds"},
+	}
+
+	for _, tc := range errorStringTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertError(t, tc.err, tc.expected)
+		})
+	}
+}
+
+func TestParseAllErrors(t *testing.T) {
+	errorTestCases := []struct {
+		name     string
+		cronExpr string
+		expected ParseErrors
+	}{
+		{name: "every bad field", cronExpr: "60 * * 13 Mon-Fri/9 cmd", expected: ParseErrors{
+			{Field: "minute", Token: "60", Offset: 0, Err: ErrOutOfBounds, Suggestion: "0"},
+			{Field: "month", Token: "13", Offset: 7, Err: ErrOutOfBounds},
+			{Field: "day of week", Token: "Mon-Fri/9", Offset: 10, Err: ErrInvalidStep, Suggestion: "MON"}}},
+		{name: "invalid character and value", cronExpr: "1.5 25 * * * cmd", expected: ParseErrors{
+			{Field: "minute", Token: ".", Offset: 1, Err: ErrInvalidCharacter},
+			{Field: "hour", Token: "25", Offset: 4, Err: ErrOutOfBounds}}},
+		{name: "missing command", cronExpr: "*/0 * * * *", expected: ParseErrors{
+			{Field: "minute", Token: "*/0", Offset: 0, Err: ErrInvalidStep, Suggestion: "*"},
+			{Offset: 11, Err: ErrFieldCount}}},
+		{name: "missing fields", cronExpr: "0 24", expected: ParseErrors{
+			{Field: "hour", Token: "24", Offset: 2, Err: ErrOutOfBounds, Suggestion: "0"},
+			{Offset: 4, Err: ErrFieldCount}}},
+		{name: "invalid value before invalid character", cronExpr: "x 1.5 * * * cmd", expected: ParseErrors{
+			{Field: "minute", Token: "x", Offset: 0, Err: ErrInvalidValue},
+			{Field: "hour", Token: ".", Offset: 3, Err: ErrInvalidCharacter}}},
+	}
+
+	for _, tc := range errorTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr, WithAllErrors())
+			assertSuccess(t, err, tc.expected, nil)
+		})
+	}
+
+	t.Run("errors.Is and errors.As", func(t *testing.T) {
+		_, err := Parse("60 * * 13 Mon-Fri/9 cmd", WithAllErrors())
+
+		var parseErr *ParseError
+		found := errors.As(err, &parseErr)
+		assertSuccess(t, found && parseErr.Field == "minute", true, nil)
+		assertSuccess(t, errors.Is(err, ErrInvalidStep), true, nil)
+		assertSuccess(t, errors.Is(err, ErrInvalidRange), false, nil)
+	})
+
+	t.Run("error string", func(t *testing.T) {
+		_, err := Parse("60 25 * * * cmd", WithAllErrors())
+		assertError(t, err, "Parsing Error: minute field \"60\": invalid value, out of bounds (did you mean \"0\"?)\nParsing Error: hour field \"25\": invalid value, out of bounds")
+	})
+
+	t.Run("valid expression", func(t *testing.T) {
+		_, err := Parse("0 5 * * * cmd", WithAllErrors())
+		if err != nil {
+			t.Fatal("error is not expected here: ", err)
+		}
+	})
+}
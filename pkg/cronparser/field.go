@@ -0,0 +1,68 @@
+package cronparser
+
+// FieldKind identifies a time field of a cron expression.
+type FieldKind int
+
+const (
+	FieldMinute FieldKind = iota
+	FieldHour
+	FieldDayOfMonth
+	FieldMonth
+	FieldDayOfWeek
+	FieldSecond
+	FieldYear
+)
+
+// TIME_FIELDS lists the standard time fields in the order they appear in an expression.
+var TIME_FIELDS = []FieldKind{FieldMinute, FieldHour, FieldDayOfMonth, FieldMonth, FieldDayOfWeek}
+
+func (f FieldKind) String() string {
+	switch f {
+	case FieldSecond:
+		return "second"
+	case FieldMinute:
+		return "minute"
+	case FieldHour:
+		return "hour"
+	case FieldDayOfMonth:
+		return "day of month"
+	case FieldMonth:
+		return "month"
+	case FieldDayOfWeek:
+		return "day of week"
+	case FieldYear:
+		return "year"
+	}
+
+	return "unknown"
+}
+
+func (f FieldKind) bounds() bound {
+	switch f {
+	case FieldSecond:
+		return SecondBound
+	case FieldMinute:
+		return MinuteBound
+	case FieldHour:
+		return HourBound
+	case FieldDayOfMonth:
+		return DOMBound
+	case FieldMonth:
+		return MonthBound
+	case FieldYear:
+		return YearBound
+	}
+
+	return DOWBound
+}
+
+func (f FieldKind) abbreviations() map[string]string {
+	switch f {
+	case FieldMonth:
+		return MONTH_ABBREVIATIONS
+	case FieldDayOfWeek:
+		return DOW_ABBREVIATIONS
+	}
+
+	return map[string]string{}
+}
@@ -0,0 +1,28 @@
+package cronparser
+
+import "testing"
+
+func TestFieldKind(t *testing.T) {
+	fieldTestCases := []struct {
+		field  FieldKind
+		name   string
+		bounds bound
+		abbr   map[string]string
+	}{
+		{field: FieldSecond, name: "second", bounds: SecondBound, abbr: map[string]string{}},
+		{field: FieldMinute, name: "minute", bounds: MinuteBound, abbr: map[string]string{}},
+		{field: FieldHour, name: "hour", bounds: HourBound, abbr: map[string]string{}},
+		{field: FieldDayOfMonth, name: "day of month", bounds: DOMBound, abbr: map[string]string{}},
+		{field: FieldMonth, name: "month", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS},
+		{field: FieldDayOfWeek, name: "day of week", bounds: DOWBound, abbr: DOW_ABBREVIATIONS},
+		{field: FieldYear, name: "year", bounds: YearBound, abbr: map[string]string{}},
+	}
+
+	for _, tc := range fieldTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertSuccess(t, tc.field.String(), tc.name, nil)
+			assertSuccess(t, tc.field.bounds(), tc.bounds, nil)
+			assertSuccess(t, tc.field.abbreviations(), tc.abbr, nil)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package cronparser
+
+import (
+	"strings"
+	"time"
+)
+
+// Scheduler is implemented by every kind of schedule ParseScheduler returns.
+type Scheduler interface {
+	// Next returns the first activation strictly after from, or the zero
+	// time.Time if there is none.
+	Next(from time.Time) time.Time
+}
+
+// IntervalSchedule fires at a fixed interval, e.g. "@every 1h30m", which
+// often can't be written as a single cron line.
+type IntervalSchedule struct {
+	interval time.Duration
+	cmd      string
+	stdin    string
+}
+
+// ParseScheduler parses interval expressions such as "@every 1h30m cmd" into
+// an IntervalSchedule, and everything else into a Schedule, as Parse does.
+// A CRON_TZ or TZ prefix is accepted before "@every" too, though an interval
+// doesn't depend on the time zone.
+func ParseScheduler(cronExpr string, opts ...Option) (Scheduler, error) {
+	everyExpr, _, err := handleTimeZone(cronExpr, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	if interval, ok, err := handleEvery(everyExpr); ok {
+		if err != nil {
+			return nil, locate(err, "", len(cronExpr)-len(everyExpr))
+		}
+
+		return interval, nil
+	}
+
+	schedule, err := Parse(cronExpr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+// handleEvery recognises "@every <duration> <cmd>", with the duration in
+// time.ParseDuration syntax and at least a second long.
+func handleEvery(cronExpr string) (*IntervalSchedule, bool, error) {
+	exprList, offsets := splitFields(cronExpr, 2)
+	if len(exprList) == 0 || strings.ToUpper(exprList[0]) != "@EVERY" {
+		return nil, false, nil
+	}
+
+	if len(exprList) != 3 {
+		return nil, true, &ParseError{Offset: len(cronExpr), Err: ErrFieldCount}
+	}
+
+	interval, err := time.ParseDuration(exprList[1])
+	if err != nil || interval < time.Second {
+		return nil, true, &ParseError{Token: exprList[1], Offset: offsets[1], Err: ErrInvalidDuration}
+	}
+
+	cmd, stdin := handleCommand(exprList[2])
+	return &IntervalSchedule{interval: interval.Truncate(time.Second), cmd: cmd, stdin: stdin}, true, nil
+}
+
+// Next returns from plus the interval, rounded down to the second.
+func (s IntervalSchedule) Next(from time.Time) time.Time {
+	return from.Add(s.interval - time.Duration(from.Nanosecond()))
+}
+
+// Interval returns the time between two activations.
+func (s IntervalSchedule) Interval() time.Duration {
+	return s.interval
+}
+
+func (s IntervalSchedule) String() string {
+	return "interval\t" + s.interval.String() + "\n" + commandLines(s.cmd, s.stdin)
+}
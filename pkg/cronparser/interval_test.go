@@ -0,0 +1,101 @@
+package cronparser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveryHandler(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "missing command", cronExpr: "@every 1h", expected: "Validation Error: invalid number of cron fields"},
+		{name: "invalid duration", cronExpr: "@every 1x cmd", expected: "Validation Error: invalid duration"},
+		{name: "negative duration", cronExpr: "@every -1h cmd", expected: "Validation Error: invalid duration"},
+		{name: "under a second", cronExpr: "@every 500ms cmd", expected: "Validation Error: invalid duration"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok, err := handleEvery(tc.cronExpr)
+			assertSuccess(t, ok, true, nil)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected *IntervalSchedule
+	}{
+		{name: "hours and minutes", cronExpr: "@every 1h30m /usr/bin/find -name x", expected: &IntervalSchedule{interval: 90 * time.Minute, cmd: "/usr/bin/find -name x"}},
+		{name: "rounded to the second", cronExpr: "@EVERY 1.5s cmd", expected: &IntervalSchedule{interval: time.Second, cmd: "cmd"}},
+		{name: "whitespace and stdin", cronExpr: "@every\t1m  mail root%hi", expected: &IntervalSchedule{interval: time.Minute, cmd: "mail root", stdin: "hi"}},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok, err := handleEvery(tc.cronExpr)
+			assertSuccess(t, ok, true, err)
+			assertSuccess(t, got, tc.expected, err)
+		})
+	}
+
+	t.Run("not an interval", func(t *testing.T) {
+		_, ok, err := handleEvery("@daily cmd")
+		assertSuccess(t, ok, false, err)
+	})
+}
+
+func TestParseScheduler(t *testing.T) {
+	from := time.Date(2024, time.May, 5, 10, 0, 0, 250, time.UTC)
+
+	nextTestCases := []struct {
+		name     string
+		cronExpr string
+		expected time.Time
+	}{
+		{name: "interval", cronExpr: "@every 1h30m cmd", expected: time.Date(2024, time.May, 5, 11, 30, 0, 0, time.UTC)},
+		{name: "cron expression", cronExpr: "*/15 * * * * cmd", expected: time.Date(2024, time.May, 5, 10, 15, 0, 0, time.UTC)},
+		{name: "macro", cronExpr: "@hourly cmd", expected: time.Date(2024, time.May, 5, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range nextTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseScheduler(tc.cronExpr)
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			assertSuccess(t, got.Next(from), tc.expected, err)
+		})
+	}
+
+	t.Run("invalid cron expression", func(t *testing.T) {
+		got, err := ParseScheduler("* * * * cmd")
+		assertError(t, err, "Validation Error: invalid number of cron fields")
+		assertSuccess(t, got == nil, true, nil)
+	})
+
+	t.Run("Parse rejects intervals", func(t *testing.T) {
+		_, err := Parse("@every 1h cmd")
+		assertError(t, err, "Validation Error: interval schedules need ParseScheduler")
+	})
+
+	t.Run("time zone prefix", func(t *testing.T) {
+		got, err := ParseScheduler("CRON_TZ=Asia/Tokyo @every 1h cmd")
+		assertSuccess(t, got, &IntervalSchedule{interval: time.Hour, cmd: "cmd"}, err)
+	})
+
+	t.Run("time zone prefix with invalid duration", func(t *testing.T) {
+		_, err := ParseScheduler("TZ=UTC @every 1x cmd")
+		assertSuccess(t, err, error(&ParseError{Token: "1x", Offset: 14, Err: ErrInvalidDuration}), nil)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		got, err := ParseScheduler("@every 90m cmd")
+		assertSuccess(t, got.(*IntervalSchedule).String(), "interval\t1h30m0s\ncommand\t\tcmd", err)
+	})
+}
@@ -0,0 +1,146 @@
+package cronparser
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenNumber   tokenKind = iota //a run of digits, e.g. "15"
+	tokenName                      //a run of letters, e.g. "MON", "L" or "H"
+	tokenAsterisk                  //*
+	tokenHyphen                    //-
+	tokenSlash                     ///
+	tokenComma                     //,
+	tokenQuestion                  //?
+	tokenHash                      //#
+	tokenTilde                     //~
+	tokenOpen                      //(
+	tokenClose                     //)
+)
+
+var punctuationTokens = map[byte]tokenKind{
+	'*': tokenAsterisk,
+	'-': tokenHyphen,
+	'/': tokenSlash,
+	',': tokenComma,
+	'?': tokenQuestion,
+	'#': tokenHash,
+	'~': tokenTilde,
+	'(': tokenOpen,
+	')': tokenClose,
+}
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int //byte offset of text in the field
+}
+
+// lex splits a time field into tokens. A character no field accepts is an
+// error pointing at that character.
+func lex(fieldExpr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(fieldExpr); {
+		start := i
+		switch c := fieldExpr[i]; {
+		case isDigit(c):
+			for i < len(fieldExpr) && isDigit(fieldExpr[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: fieldExpr[start:i], offset: start})
+		case isLetter(c):
+			for i < len(fieldExpr) && isLetter(fieldExpr[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenName, text: fieldExpr[start:i], offset: start})
+		default:
+			kind, ok := punctuationTokens[c]
+			if !ok {
+				//the whole character, which may take more than a byte
+				_, size := utf8.DecodeRuneInString(fieldExpr[i:])
+				return nil, &ParseError{Token: fieldExpr[i : i+size], Offset: i, Err: ErrInvalidCharacter}
+			}
+
+			i++
+			tokens = append(tokens, token{kind: kind, text: fieldExpr[start:i], offset: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+// splitTerms splits the tokens of a field at its commas, returning each term
+// with its offset in the field. A comma with no term before or after it, as in
+// "1,,2" or "1,", is an error at that comma.
+func splitTerms(tokens []token) ([][]token, []int, error) {
+	terms, offsets := [][]token{}, []int{0}
+	start := 0
+	for i, tok := range tokens {
+		if tok.kind != tokenComma {
+			continue
+		}
+
+		if i == start || i == len(tokens)-1 {
+			return nil, nil, &tokenError{tok: tok, err: ErrUnexpectedToken}
+		}
+
+		terms = append(terms, tokens[start:i])
+		offsets = append(offsets, tok.offset+1)
+		start = i + 1
+	}
+
+	terms = append(terms, tokens[start:])
+	return terms, offsets, nil
+}
+
+// matchTokens reports whether tokens are exactly of the given kinds.
+func matchTokens(tokens []token, kinds ...tokenKind) bool {
+	if len(tokens) != len(kinds) {
+		return false
+	}
+
+	for i, kind := range kinds {
+		if tokens[i].kind != kind {
+			return false
+		}
+	}
+
+	return true
+}
+
+func indexToken(tokens []token, kind tokenKind) int {
+	for i, tok := range tokens {
+		if tok.kind == kind {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func joinTokens(tokens []token) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteString(tok.text)
+	}
+
+	return sb.String()
+}
+
+func numberToken(val int, offset int) token {
+	return token{kind: tokenNumber, text: strconv.Itoa(val), offset: offset}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
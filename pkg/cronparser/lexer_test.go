@@ -0,0 +1,121 @@
+package cronparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lexTerm lexes a term the lexer is known to accept, for the handler tests.
+func lexTerm(expr string) []token {
+	tokens, err := lex(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return tokens
+}
+
+func TestLexer(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		expr     string
+		expected *ParseError
+	}{
+		{name: "unknown character", expr: "1-5@", expected: &ParseError{Token: "@", Offset: 3, Err: ErrInvalidCharacter}},
+		{name: "dot", expr: "1.5", expected: &ParseError{Token: ".", Offset: 1, Err: ErrInvalidCharacter}},
+		{name: "percent first", expr: "%", expected: &ParseError{Token: "%", Offset: 0, Err: ErrInvalidCharacter}},
+		{name: "whole rune", expr: "1é", expected: &ParseError{Token: "é", Offset: 1, Err: ErrInvalidCharacter}},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := lex(tc.expr)
+			if !reflect.DeepEqual(err, tc.expected) {
+				t.Errorf("expected %#v but got %#v", tc.expected, err)
+			}
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		expr     string
+		expected []token
+	}{
+		{name: "empty", expr: "", expected: nil},
+		{name: "stepped range", expr: "10-20/5", expected: []token{
+			{kind: tokenNumber, text: "10", offset: 0},
+			{kind: tokenHyphen, text: "-", offset: 2},
+			{kind: tokenNumber, text: "20", offset: 3},
+			{kind: tokenSlash, text: "/", offset: 5},
+			{kind: tokenNumber, text: "5", offset: 6},
+		}},
+		{name: "list of names", expr: "MON,*", expected: []token{
+			{kind: tokenName, text: "MON", offset: 0},
+			{kind: tokenComma, text: ",", offset: 3},
+			{kind: tokenAsterisk, text: "*", offset: 4},
+		}},
+		{name: "number then name", expr: "15W", expected: []token{
+			{kind: tokenNumber, text: "15", offset: 0},
+			{kind: tokenName, text: "W", offset: 2},
+		}},
+		{name: "quartz and jenkins", expr: "?#H(0-9)~", expected: []token{
+			{kind: tokenQuestion, text: "?", offset: 0},
+			{kind: tokenHash, text: "#", offset: 1},
+			{kind: tokenName, text: "H", offset: 2},
+			{kind: tokenOpen, text: "(", offset: 3},
+			{kind: tokenNumber, text: "0", offset: 4},
+			{kind: tokenHyphen, text: "-", offset: 5},
+			{kind: tokenNumber, text: "9", offset: 6},
+			{kind: tokenClose, text: ")", offset: 7},
+			{kind: tokenTilde, text: "~", offset: 8},
+		}},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := lex(tc.expr)
+			assertSuccess(t, got, tc.expected, err)
+		})
+	}
+}
+
+func TestSplitTerms(t *testing.T) {
+	terms, offsets, err := splitTerms(lexTerm("1,MON-FRI,5"))
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	expected := []string{"1", "MON-FRI", "5"}
+	got := make([]string, len(terms))
+	for i, term := range terms {
+		got[i] = joinTokens(term)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q but got %q", expected, got)
+	}
+
+	expectedOffsets := []int{0, 2, 10}
+	if !reflect.DeepEqual(offsets, expectedOffsets) {
+		t.Errorf("expected %v but got %v", expectedOffsets, offsets)
+	}
+
+	strayCommaTestCases := []struct {
+		name     string
+		expr     string
+		expected *ParseError
+	}{
+		{name: "empty term", expr: "1,,2", expected: &ParseError{Token: ",", Offset: 2, Err: ErrUnexpectedToken}},
+		{name: "leading comma", expr: ",1", expected: &ParseError{Token: ",", Offset: 0, Err: ErrUnexpectedToken}},
+		{name: "trailing comma", expr: "1,", expected: &ParseError{Token: ",", Offset: 1, Err: ErrUnexpectedToken}},
+	}
+
+	for _, tc := range strayCommaTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := splitTerms(lexTerm(tc.expr))
+			if got := termError(nil, 0, err); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %#v but got %#v", tc.expected, got)
+			}
+		})
+	}
+}
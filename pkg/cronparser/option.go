@@ -0,0 +1,106 @@
+package cronparser
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Option configures how Parse and ParseInLocation build a Schedule.
+type Option func(*config)
+
+type config struct {
+	dstPolicy DSTPolicy
+	dayMatch  DayMatch
+	seconds   bool
+	year      bool
+	hash      *rand.Rand
+	rand      *rand.Rand
+	wrap      bool
+	allErrors bool
+}
+
+// WithDSTPolicy sets how the schedule treats wall clock times that a daylight
+// saving transition skips or repeats. The default is DSTRunOnce.
+func WithDSTPolicy(policy DSTPolicy) Option {
+	return func(cfg *config) {
+		cfg.dstPolicy = policy
+	}
+}
+
+// WithDayMatch sets how day of month and day of week combine when both are
+// restricted. The default is DayMatchOr, as in Vixie cron.
+func WithDayMatch(dayMatch DayMatch) Option {
+	return func(cfg *config) {
+		cfg.dayMatch = dayMatch
+	}
+}
+
+// WithSeconds expects a leading seconds field (0-59) before the minute field,
+// as in Spring and Quartz expressions.
+func WithSeconds() Option {
+	return func(cfg *config) {
+		cfg.seconds = true
+	}
+}
+
+// WithYear expects a trailing year field (1970-2099) after the day of week
+// field, as in Quartz and AWS expressions.
+func WithYear() Option {
+	return func(cfg *config) {
+		cfg.year = true
+	}
+}
+
+// WithWrapAround lets a range run past the end of its field back to the
+// start, so that "FRI-MON" or hour "22-2/2" wrap instead of being rejected.
+func WithWrapAround() Option {
+	return func(cfg *config) {
+		cfg.wrap = true
+	}
+}
+
+// WithAllErrors checks every field and the command before giving up, and
+// returns all the problems found as ParseErrors, in the order they appear in
+// the expression, instead of only the first.
+func WithAllErrors() Option {
+	return func(cfg *config) {
+		cfg.allErrors = true
+	}
+}
+
+// WithHashKey lets fields use the Jenkins H, whose values are derived from
+// key (such as a job name), so that jobs sharing an expression are spread out
+// while each one keeps the same schedule every time it is parsed.
+func WithHashKey(key string) Option {
+	return func(cfg *config) {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		cfg.hash = rand.New(rand.NewSource(int64(h.Sum64())))
+	}
+}
+
+// WithRand sets the source "~" picks its random values from, so that tests
+// can reproduce them. By default it is seeded from the current time.
+func WithRand(r *rand.Rand) Option {
+	return func(cfg *config) {
+		cfg.rand = r
+	}
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{dstPolicy: DSTRunOnce, dayMatch: DayMatchOr}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+func (cfg *config) random() *rand.Rand {
+	if cfg.rand == nil {
+		cfg.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	return cfg.rand
+}
@@ -0,0 +1,60 @@
+package cronparser
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		got := newConfig(nil)
+		assertSuccess(t, got, &config{dstPolicy: DSTRunOnce, dayMatch: DayMatchOr}, nil)
+	})
+
+	t.Run("dst policy", func(t *testing.T) {
+		got := newConfig([]Option{WithDSTPolicy(DSTSkip)})
+		assertSuccess(t, got.dstPolicy, DSTSkip, nil)
+	})
+
+	t.Run("day match", func(t *testing.T) {
+		got := newConfig([]Option{WithDayMatch(DayMatchAnd)})
+		assertSuccess(t, got.dayMatch, DayMatchAnd, nil)
+	})
+
+	t.Run("seconds", func(t *testing.T) {
+		got := newConfig([]Option{WithSeconds()})
+		assertSuccess(t, got.seconds, true, nil)
+	})
+
+	t.Run("year", func(t *testing.T) {
+		got := newConfig([]Option{WithYear()})
+		assertSuccess(t, got.year, true, nil)
+	})
+
+	t.Run("wrap around", func(t *testing.T) {
+		got := newConfig([]Option{WithWrapAround()})
+		assertSuccess(t, got.wrap, true, nil)
+	})
+
+	t.Run("all errors", func(t *testing.T) {
+		got := newConfig([]Option{WithAllErrors()})
+		assertSuccess(t, got.allErrors, true, nil)
+	})
+
+	t.Run("hash key", func(t *testing.T) {
+		got := newConfig([]Option{WithHashKey("job")})
+		assertSuccess(t, got.hash.Intn(60), newConfig([]Option{WithHashKey("job")}).hash.Intn(60), nil)
+	})
+
+	t.Run("rand", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		got := newConfig([]Option{WithRand(r)})
+		assertSuccess(t, got.random(), r, nil)
+	})
+
+	t.Run("no rand without ~", func(t *testing.T) {
+		got := newConfig(nil)
+		_, _, err := parseField("*/15,1-5", MinuteBound, map[string]string{}, got)
+		assertSuccess(t, got.rand == nil, true, err)
+	})
+}
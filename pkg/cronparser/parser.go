@@ -1,27 +1,46 @@
 package cronparser
 
 import (
-	"errors"
 	"fmt"
-	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
-const VALID_NUM_OF_CRON_FIELDS = 6
-
 type bound struct {
 	min, max int
 }
 
+var SecondBound = bound{0, 59}
 var MinuteBound = bound{0, 59}
 var HourBound = bound{0, 23}
 var DOMBound = bound{1, 31}
 var MonthBound = bound{1, 12}
 var DOWBound = bound{0, 6}
-
-var DOW_ABBREVIATIONS = map[string]string{"SUN": "0", "MON": "1", "TUE": "2", "WED": "3", "THU": "4", "FRI": "5", "SAT": "6"}
-var MONTH_ABBREVIATIONS = map[string]string{"JAN": "1", "FEB": "2", "MAR": "3", "APR": "4", "MAY": "5", "JUN": "6", "JUL": "7", "AUG": "8", "SEP": "9", "OCT": "10", "NOV": "11", "DEC": "12"}
+var YearBound = bound{1970, 2099}
+
+const SUNDAY_ALIAS = 7  //day of week 7 is Sunday too, as in Vixie cron
+const HASH_DOM_MAX = 28 //a day of month H stays on days every month has, as in Jenkins
+
+// abbreviations and full names
+var DOW_ABBREVIATIONS = map[string]string{
+	"SUN": "0", "MON": "1", "TUE": "2", "WED": "3", "THU": "4", "FRI": "5", "SAT": "6",
+	"SUNDAY": "0", "MONDAY": "1", "TUESDAY": "2", "WEDNESDAY": "3", "THURSDAY": "4", "FRIDAY": "5", "SATURDAY": "6"}
+var MONTH_ABBREVIATIONS = map[string]string{
+	"JAN": "1", "FEB": "2", "MAR": "3", "APR": "4", "MAY": "5", "JUN": "6", "JUL": "7", "AUG": "8", "SEP": "9", "OCT": "10", "NOV": "11", "DEC": "12",
+	"JANUARY": "1", "FEBRUARY": "2", "MARCH": "3", "APRIL": "4", "JUNE": "6", "JULY": "7", "AUGUST": "8", "SEPTEMBER": "9", "OCTOBER": "10", "NOVEMBER": "11", "DECEMBER": "12"}
+
+var TIME_ZONE_PREFIXES = []string{"CRON_TZ=", "TZ="}
+
+var MACROS = map[string]string{
+	"@YEARLY":   "0 0 1 1 *",
+	"@ANNUALLY": "0 0 1 1 *",
+	"@MONTHLY":  "0 0 1 * *",
+	"@WEEKLY":   "0 0 * * 0",
+	"@DAILY":    "0 0 * * *",
+	"@MIDNIGHT": "0 0 * * *",
+	"@HOURLY":   "0 * * * *",
+}
 
 func PrintCronSchedule(cronExpr string) {
 	defer func() {
@@ -30,7 +49,7 @@ func PrintCronSchedule(cronExpr string) {
 		}
 	}()
 
-	schedule, err := Parse(cronExpr)
+	schedule, err := ParseScheduler(cronExpr)
 	if err != nil {
 		panic(err)
 	}
@@ -38,81 +57,287 @@ func PrintCronSchedule(cronExpr string) {
 	fmt.Println(schedule)
 }
 
-func Parse(cronExpr string) (*Schedule, error) {
-	cronFields, err := validate(cronExpr)
+// Parse parses cronExpr with its time fields interpreted in UTC,
+// unless the expression starts with a CRON_TZ= or TZ= prefix.
+func Parse(cronExpr string, opts ...Option) (*Schedule, error) {
+	return ParseInLocation(cronExpr, time.UTC, opts...)
+}
+
+// ParseInLocation is like Parse but interprets the time fields in loc.
+// A CRON_TZ=<zone> or TZ=<zone> prefix in cronExpr takes precedence over loc.
+func ParseInLocation(cronExpr string, loc *time.Location, opts ...Option) (*Schedule, error) {
+	cfg := newConfig(opts)
+
+	input := cronExpr
+	cronExpr, loc, err := handleTimeZone(cronExpr, loc)
 	if err != nil {
 		return nil, err
 	}
 
-	minute, err := parseField(cronFields[0], MinuteBound, map[string]string{})
-	if err != nil {
-		return nil, err
+	//offset of what is left of the expression once the time zone is taken off
+	base := len(input) - len(cronExpr)
+
+	if cmdExpr, ok, err := handleReboot(cronExpr); ok {
+		if err != nil {
+			return nil, locate(err, "", base)
+		}
+
+		cmd, stdin := handleCommand(cmdExpr)
+		return &Schedule{kind: KindReboot, cmd: cmd, stdin: stdin, location: loc}, nil
 	}
 
-	hour, err := parseField(cronFields[1], HourBound, map[string]string{})
+	macroExpr := cronExpr
+	cronExpr, err = handleMacro(cronExpr, cfg)
 	if err != nil {
-		return nil, err
+		return nil, locate(err, "", base)
 	}
 
-	dom, err := parseField(cronFields[2], DOMBound, map[string]string{})
-	if err != nil {
-		return nil, err
+	var fields []FieldKind
+	if cfg.seconds {
+		fields = append(fields, FieldSecond)
 	}
 
-	month, err := parseField(cronFields[3], MonthBound, MONTH_ABBREVIATIONS)
-	if err != nil {
-		return nil, err
+	fields = append(fields, TIME_FIELDS...)
+	if cfg.year {
+		fields = append(fields, FieldYear)
 	}
 
-	dow, err := parseField(cronFields[4], DOWBound, DOW_ABBREVIATIONS)
-	if err != nil {
-		return nil, err
+	cronFields, offsets, errs := validate(cronExpr, fields)
+	for _, err := range errs {
+		if err.Err == ErrFieldCount && macroExpr != cronExpr {
+			//the end of the macro as written, not of the fields it stands for
+			err.Offset = len(macroExpr)
+		}
+
+		asWritten(locate(err, "", base), input)
+	}
+
+	if len(errs) > 0 && !cfg.allErrors {
+		return nil, errs[0]
+	}
+
+	values := make(map[FieldKind][]int)
+	specs := make(map[FieldKind][]daySpec)
+	wildcard := make(map[FieldKind]bool)
+	random := make(map[FieldKind]bool)
+	picked := make(map[FieldKind][]int)
+	for i, field := range fields {
+		if i >= len(cronFields) || errs.has(field) {
+			continue
+		}
+
+		if field == FieldDayOfMonth || field == FieldDayOfWeek {
+			values[field], specs[field], picked[field], err = parseDayField(cronFields[i], field, cfg)
+		} else {
+			values[field], picked[field], err = parseField(cronFields[i], field.bounds(), field.abbreviations(), cfg)
+		}
+
+		if err != nil {
+			parseErr := asWritten(locate(err, field.String(), base+offsets[i]), input).(*ParseError)
+			suggest(parseErr, field)
+			if !cfg.allErrors {
+				return nil, parseErr
+			}
+
+			errs = append(errs, parseErr)
+			continue
+		}
+
+		wildcard[field] = strings.HasPrefix(cronFields[i], "*") || cronFields[i] == "?"
+		random[field] = strings.Contains(cronFields[i], "~")
 	}
 
+	if len(errs) > 0 {
+		//in the order they appear in the expression
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Offset < errs[j].Offset })
+		return nil, errs
+	}
+
+	//the fields of a macro are spanned by the macro itself
+	macroList, macroOffsets := splitFields(macroExpr, 1)
+	macroSpan := Span{base + macroOffsets[0], base + macroOffsets[0] + len(macroList[0])}
+
+	ast := make([]Field, len(fields))
+	for i, field := range fields {
+		if macroExpr != cronExpr {
+			ast[i] = newField(field, cronFields[i], 0)
+			ast[i].at(macroSpan)
+			continue
+		}
+
+		start := base + offsets[i]
+		ast[i] = newField(field, input[start:start+len(cronFields[i])], start)
+	}
+
+	cmd, stdin := handleCommand(cronFields[len(fields)])
+
 	return &Schedule{
-		minute: minute,
-		hour:   hour,
-		dom:    dom,
-		month:  month,
-		dow:    dow,
-		cmd:    cronFields[5]}, nil
+		second:    values[FieldSecond],
+		minute:    values[FieldMinute],
+		hour:      values[FieldHour],
+		dom:       values[FieldDayOfMonth],
+		month:     values[FieldMonth],
+		dow:       values[FieldDayOfWeek],
+		domSpecs:  specs[FieldDayOfMonth],
+		dowSpecs:  specs[FieldDayOfWeek],
+		year:      values[FieldYear],
+		cmd:       cmd,
+		stdin:     stdin,
+		location:  loc,
+		dstPolicy: cfg.dstPolicy,
+		dayMatch:  cfg.dayMatch,
+		wildcard:  wildcard,
+		random:    random,
+		picked:    picked,
+		fields:    ast}, nil
 }
 
-func validate(cronExpr string) ([]string, error) {
-	cronFields := strings.Split(cronExpr, " ")
-	if len(cronFields) != VALID_NUM_OF_CRON_FIELDS {
-		return nil, errors.New("Validation Error: invalid number of cron fields")
+func handleTimeZone(cronExpr string, loc *time.Location) (string, *time.Location, error) {
+	for _, prefix := range TIME_ZONE_PREFIXES {
+		if !strings.HasPrefix(cronExpr, prefix) {
+			continue
+		}
+
+		exprList, offsets := splitFields(strings.TrimPrefix(cronExpr, prefix), 1)
+		if len(exprList) != 2 {
+			return "", nil, &ParseError{Offset: len(cronExpr), Err: ErrFieldCount}
+		}
+
+		zone, err := time.LoadLocation(exprList[0])
+		if err != nil {
+			return "", nil, &ParseError{Token: exprList[0], Offset: len(prefix) + offsets[0], Err: ErrTimeZone}
+		}
+
+		return exprList[1], zone, nil
 	}
 
-	pattern := `[/*/,/-/\0-9]|(MON|TUE|WED|THU|FRI|SAT|SUN)|(JAN|FEB|MAR|APR|MAY|JUN|JUL|AUG|SEP|OCT|NOV|DEC)`
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, errors.New("Validation Error: failed to compile Regexp")
+	if loc == nil {
+		return "", nil, &ParseError{Err: ErrTimeZone}
+	}
+
+	return cronExpr, loc, nil
+}
+
+// handleReboot recognises "@reboot <cmd>" and returns its command.
+func handleReboot(cronExpr string) (string, bool, error) {
+	exprList, _ := splitFields(cronExpr, 1)
+	if len(exprList) == 0 || strings.ToUpper(exprList[0]) != "@REBOOT" {
+		return "", false, nil
+	}
+
+	if len(exprList) != 2 {
+		return "", true, &ParseError{Offset: len(cronExpr), Err: ErrFieldCount}
+	}
+
+	return exprList[1], true, nil
+}
+
+// handleMacro expands a leading macro such as @daily into the time fields it
+// stands for, padded with the seconds and year fields the options expect.
+func handleMacro(cronExpr string, cfg *config) (string, error) {
+	if !strings.HasPrefix(cronExpr, "@") {
+		return cronExpr, nil
+	}
+
+	exprList, offsets := splitFields(cronExpr, 1)
+	if strings.ToUpper(exprList[0]) == "@EVERY" {
+		return "", &ParseError{Token: exprList[0], Offset: offsets[0], Err: ErrIntervalSchedule}
+	}
+
+	timeFields, ok := MACROS[strings.ToUpper(exprList[0])]
+	if !ok {
+		return "", &ParseError{Token: exprList[0], Offset: offsets[0], Err: ErrMacro, Suggestion: suggestName(strings.ToUpper(exprList[0]), MACROS)}
+	}
+
+	if cfg.seconds {
+		timeFields = "0 " + timeFields
 	}
 
-	cmdIndex := VALID_NUM_OF_CRON_FIELDS - 1
-	for i := 0; i < cmdIndex; i++ {
+	if cfg.year {
+		timeFields += " *"
+	}
+
+	exprList[0] = timeFields
+	return strings.Join(exprList, " "), nil
+}
+
+// validate splits cronExpr into the given time fields and the command, which
+// is the rest of the line with any arguments, along with their offsets. It
+// reports every problem it finds, checking the fields present even when some
+// are missing.
+func validate(cronExpr string, fields []FieldKind) ([]string, []int, ParseErrors) {
+	var errs ParseErrors
+
+	numOfFields := len(fields) + 1
+	cronFields, offsets := splitFields(cronExpr, numOfFields-1)
+	if len(cronFields) != numOfFields {
+		errs = append(errs, &ParseError{Offset: len(cronExpr), Err: ErrFieldCount})
+	}
+
+	for i := 0; i < len(cronFields) && i < len(fields); i++ {
 		cronFields[i] = strings.ToUpper(cronFields[i])
-		if !re.MatchString(cronFields[i]) {
-			return nil, errors.New("Validation Error: invalid time field")
+		if _, err := lex(cronFields[i]); err != nil {
+			errs = append(errs, locate(err, fields[i].String(), offsets[i]).(*ParseError))
+		}
+	}
+
+	return cronFields, offsets, errs
+}
+
+// handleCommand applies Vixie's % convention to the command: the first % ends
+// the command and the rest, with each further % as a newline, is its standard
+// input. "\%" stands for a literal %.
+func handleCommand(cmdExpr string) (cmd, stdin string) {
+	var sb strings.Builder
+	hasStdin := false
+	for i := 0; i < len(cmdExpr); i++ {
+		switch {
+		case cmdExpr[i] == '\\' && i+1 < len(cmdExpr) && cmdExpr[i+1] == '%':
+			sb.WriteByte('%')
+			i++
+		case cmdExpr[i] == '%' && !hasStdin:
+			cmd = sb.String()
+			sb.Reset()
+			hasStdin = true
+		case cmdExpr[i] == '%':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(cmdExpr[i])
 		}
 	}
 
-	return cronFields, nil
+	if !hasStdin {
+		return sb.String(), ""
+	}
+
+	return cmd, sb.String()
 }
 
-func parseField(fieldExpr string, bounds bound, abbreviationMap map[string]string) ([]int, error) {
+// parseField parses a time field into its values, along with the values
+// picked for its "~" terms in the order they are written.
+func parseField(fieldExpr string, bounds bound, abbreviationMap map[string]string, cfg *config) ([]int, []int, error) {
+	tokens, err := lex(fieldExpr)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	uniqueValueMap := make(map[int]struct{})
+	var picked []int
 
 	//handleComma:
-	exprs := strings.Split(fieldExpr, ",")
-	for _, expr := range exprs {
-		valueList, err := handleNonComma(expr, bounds, abbreviationMap)
+	terms, offsets, err := splitTerms(tokens)
+	if err != nil {
+		return nil, nil, termError(nil, 0, err)
+	}
+
+	for i, term := range terms {
+		valueList, termPicked, err := handleNonComma(term, bounds, abbreviationMap, cfg)
 		if err != nil {
-			err := errors.New("Parsing Error: " + err.Error())
-			return nil, err
+			return nil, nil, termError(term, offsets[i], err)
 		}
 
+		picked = append(picked, termPicked...)
+
 		for _, val := range valueList {
 			uniqueValueMap[val] = struct{}{}
 		}
@@ -125,35 +350,126 @@ func parseField(fieldExpr string, bounds bound, abbreviationMap map[string]strin
 
 	sort.Ints(uniqueValueList)
 
-	return uniqueValueList, nil
+	return uniqueValueList, picked, nil
+}
+
+// parseDayField parses a day of month or day of week field. Quartz-style
+// values that depend on the month (L, W, #) are set aside as daySpecs, and a
+// "?" (no specific value) stands for every day. As with parseField, the values
+// picked for "~" terms are returned as well.
+func parseDayField(fieldExpr string, field FieldKind, cfg *config) ([]int, []daySpec, []int, error) {
+	if fieldExpr == "?" {
+		fieldExpr = "*"
+	}
+
+	tokens, err := lex(fieldExpr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var specs []daySpec
+	var picked []int
+	values := []int{}
+	terms, offsets, err := splitTerms(tokens)
+	if err != nil {
+		return nil, nil, nil, termError(nil, 0, err)
+	}
+
+	for i, term := range terms {
+		spec, dayTerm, err := handleDaySpec(term, field)
+		if err != nil {
+			return nil, nil, nil, termError(term, offsets[i], err)
+		}
+
+		if spec != nil {
+			specs = append(specs, *spec)
+			continue
+		}
+
+		termValues, termPicked, err := handleNonComma(dayTerm, field.bounds(), field.abbreviations(), cfg)
+		if err != nil {
+			return nil, nil, nil, termError(term, offsets[i], err)
+		}
+
+		values = append(values, termValues...)
+		picked = append(picked, termPicked...)
+	}
+
+	return uniqueInts(values), specs, picked, nil
+}
+
+// handleDaySpec picks a month dependent value out of a day field term. When
+// the term isn't one, it returns the tokens left for handleNonComma.
+func handleDaySpec(term []token, field FieldKind) (*daySpec, []token, error) {
+	cf := NewCronField(term)
+
+	if err := cf.handleLast(field, field.abbreviations()); err != nil {
+		return nil, nil, err
+	}
+
+	if err := cf.handleNearestWeekday(field); err != nil {
+		return nil, nil, err
+	}
+
+	if err := cf.handleNth(field, field.abbreviations()); err != nil {
+		return nil, nil, err
+	}
+
+	return cf.spec, cf.tokens, nil
 }
 
-func handleNonComma(expr string, bounds bound, abbreviationMap map[string]string) ([]int, error) {
+func handleNonComma(term []token, bounds bound, abbreviationMap map[string]string, cfg *config) ([]int, []int, error) {
 	var err error
 
-	cf := NewCronField(expr)
+	cf := NewCronField(term)
+	cf.wrap = cfg.wrap
+
+	if err = cf.handleHash(bounds, cfg.hash); err != nil {
+		return nil, nil, err
+	}
+
+	if err = cf.handleRandom(bounds, abbreviationMap, cfg.random); err != nil {
+		return nil, nil, err
+	}
 
 	if err = cf.handleSlash(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err = cf.handleAsterisk(bounds); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	if err = cf.handleSingleValue(); err != nil {
-		return nil, err
+	if err = cf.handleSingleValue(bounds); err != nil {
+		return nil, nil, err
 	}
 
 	if err = cf.handleHyphen(abbreviationMap); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if err = cf.handleSundayAlias(bounds); err != nil {
+		return nil, nil, err
 	}
 
 	if err = cf.handleInvalidExpr(bounds, FRInitBounds); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if cf.min > cf.max {
+		cf.valueList = buildWrappedIntList(cf.min, cf.max, cf.interval, bounds)
+	} else {
+		cf.valueList = buildIntList(cf.min, cf.max, cf.interval)
+	}
+
+	if cf.sunday {
+		cf.valueList = append([]int{DOWBound.min}, cf.valueList...)
 	}
 
-	cf.valueList = buildIntList(cf.min, cf.max, cf.interval)
+	//a "~" term comes down to the one value it picked
+	if indexToken(term, tokenTilde) != -1 {
+		return cf.valueList, cf.valueList[:1], nil
+	}
 
-	return cf.valueList, nil
+	return cf.valueList, nil, nil
 }
@@ -1,7 +1,10 @@
 package cronparser
 
 import (
+	"math/rand"
 	"testing"
+	"time"
+	_ "time/tzdata"
 )
 
 func TestValidator(t *testing.T) {
@@ -10,22 +13,40 @@ func TestValidator(t *testing.T) {
 		cronExpr string
 		expected string
 	}{
-		{name: "extra space in separator", cronExpr: "*/15 0 1, 2 2 1-5 /usr/bin/find", expected: "Validation Error: invalid number of cron fields"},
+		{name: "missing command", cronExpr: "*/15 0 1,15 2 1-5", expected: "Validation Error: invalid number of cron fields"},
+		{name: "only whitespace after fields", cronExpr: "*/15 0 1,15 2 1-5 \t ", expected: "Validation Error: invalid number of cron fields"},
 		{name: "invalid number of fields", cronExpr: "*/15 0 1,15 1-5 /usr/bin/find", expected: "Validation Error: invalid number of cron fields"},
-		{name: "invalid special character", cronExpr: "*/15 0 ? 1 1-5 /usr/bin/find", expected: "Validation Error: invalid time field"},
-		{name: "invalid special character", cronExpr: "*/15 0 L 2 1-5 /usr/bin/find", expected: "Validation Error: invalid time field"},
+		{name: "invalid special character", cronExpr: "*/15 0 1.5 1 1-5 /usr/bin/find", expected: "Parsing Error: day of month field \".\": invalid character"},
+		{name: "invalid special character", cronExpr: "*/15 0 1 2 @ /usr/bin/find", expected: "Parsing Error: day of week field \"@\": invalid character"},
 	}
 
 	for _, tc := range failureTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := validate(tc.cronExpr)
+			_, _, err := validate(tc.cronExpr, TIME_FIELDS)
 			assertError(t, err, tc.expected)
 		})
 	}
 
+	t.Run("command with arguments", func(t *testing.T) {
+		got, _, errs := validate("*/15\t0  1 jan Mon /usr/bin/find /tmp  -mtime +7", TIME_FIELDS)
+		if errs != nil {
+			t.Fatal("error is not expected here, but got one: ", errs)
+		}
+
+		assertSuccess(t, got, []string{"*/15", "0", "1", "JAN", "MON", "/usr/bin/find /tmp  -mtime +7"}, nil)
+	})
+
 	t.Run("valid case with abbr", func(t *testing.T) {
 		cronExpr := "*/15 0 1 jan Mon /usr/bin/find"
-		_, err := validate(cronExpr)
+		_, _, err := validate(cronExpr, TIME_FIELDS)
+		if err != nil {
+			t.Fatal("error is not expected here, but got one: ", err)
+		}
+	})
+
+	t.Run("valid case with quartz special characters", func(t *testing.T) {
+		cronExpr := "*/15 0 LW * 5#3,? /usr/bin/find"
+		_, _, err := validate(cronExpr, TIME_FIELDS)
 		if err != nil {
 			t.Fatal("error is not expected here, but got one: ", err)
 		}
@@ -40,19 +61,21 @@ func TestNonCommaHandler(t *testing.T) {
 		abbr     map[string]string
 		expected string
 	}{
-		{name: "invalid every instant", expr: "2*", bounds: MinuteBound, abbr: map[string]string{}, expected: "strconv.Atoi: parsing \"2*\": invalid syntax"},
+		{name: "invalid every instant", expr: "2*", bounds: MinuteBound, abbr: map[string]string{}, expected: "unexpected token"},
 		{name: "invalid one instant", expr: "60", bounds: MinuteBound, abbr: map[string]string{}, expected: "invalid value, out of bounds"},
 		{name: "invalid regular instants", expr: "*/26", bounds: HourBound, abbr: map[string]string{}, expected: "invalid interval"},
 		{name: "invalid bounded instants", expr: "1-32", bounds: DOMBound, abbr: map[string]string{}, expected: "invalid value, out of bounds"},
 		{name: "invalid bounded regular instants", expr: "1-12/2", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: "invalid value, out of bounds"},
+		{name: "step as long as the field", expr: "1-4/7", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: "invalid interval"},
+		{name: "step as long as the field from a start", expr: "1/60", bounds: MinuteBound, abbr: map[string]string{}, expected: "invalid interval"},
 		{name: "invalid bounded regular instants 2", expr: "1-4/8", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: "invalid interval"},
 		{name: "invalid bounded regular instants 2", expr: "Dec-Jan", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "invalid bounds"},
-		{name: "invalid special char", expr: "L", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "strconv.Atoi: parsing \"L\": invalid syntax"},
+		{name: "invalid special char", expr: "L", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "invalid value"},
 	}
 
 	for _, tc := range failureTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := handleNonComma(tc.expr, tc.bounds, tc.abbr)
+			_, _, err := handleNonComma(lexTerm(tc.expr), tc.bounds, tc.abbr, newConfig(nil))
 			assertError(t, err, tc.expected)
 		})
 	}
@@ -68,14 +91,28 @@ func TestNonCommaHandler(t *testing.T) {
 		{name: "every instant", expr: "*", bounds: MinuteBound, abbr: map[string]string{}, expected: buildIntList(MinuteBound.min, MinuteBound.max, 1)},
 		{name: "regular instants", expr: "*/4", bounds: HourBound, abbr: map[string]string{}, expected: buildIntList(HourBound.min, HourBound.max, 4)},
 		{name: "bounded instants", expr: "1-15", bounds: DOMBound, abbr: map[string]string{}, expected: buildIntList(1, 15, 1)},
-		{name: "bound regular instants", expr: "1-4/7", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: buildIntList(1, 1, 1)},
+		{name: "bound regular instants", expr: "1-4/6", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: buildIntList(1, 1, 1)},
 		{name: "one abbr instant", expr: "jul", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: []int{7}},
 		{name: "one abbr instant", expr: "MOn", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{1}},
+		{name: "seven as sunday", expr: "7", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{0}},
+		{name: "range to seven", expr: "5-7", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{0, 5, 6}},
+		{name: "stepped range to seven", expr: "1-7/3", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{0, 1, 4}},
+		{name: "full day name", expr: "Monday-Wednesday", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{1, 2, 3}},
+		{name: "full month name", expr: "September", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: []int{9}},
+		{name: "stepped second", expr: "10/20", bounds: SecondBound, abbr: map[string]string{}, expected: []int{10, 30, 50}},
+		{name: "stepped minute", expr: "5/15", bounds: MinuteBound, abbr: map[string]string{}, expected: []int{5, 20, 35, 50}},
+		{name: "stepped hour", expr: "3/6", bounds: HourBound, abbr: map[string]string{}, expected: []int{3, 9, 15, 21}},
+		{name: "stepped day of month", expr: "20/5", bounds: DOMBound, abbr: map[string]string{}, expected: []int{20, 25, 30}},
+		{name: "stepped month", expr: "2/3", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: []int{2, 5, 8, 11}},
+		{name: "stepped month abbr", expr: "Mar/4", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: []int{3, 7, 11}},
+		{name: "stepped day of week", expr: "2/2", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{2, 4, 6}},
+		{name: "stepped day of week abbr", expr: "MON/2", bounds: DOWBound, abbr: DOW_ABBREVIATIONS, expected: []int{1, 3, 5}},
+		{name: "stepped year", expr: "2090/4", bounds: YearBound, abbr: map[string]string{}, expected: []int{2090, 2094, 2098}},
 	}
 
 	for _, tc := range successTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := handleNonComma(tc.expr, tc.bounds, tc.abbr)
+			got, _, err := handleNonComma(lexTerm(tc.expr), tc.bounds, tc.abbr, newConfig(nil))
 			assertSuccess(t, got, tc.expected, err)
 		})
 	}
@@ -87,17 +124,18 @@ func TestParseField(t *testing.T) {
 		expr     string
 		bounds   bound
 		abbr     map[string]string
-		expected string
+		expected *ParseError
 	}{
-		{name: "FC: out of bounds", expr: "1,4,13", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "Parsing Error: invalid value, out of bounds"},
-		{name: "FC: chars in expr", expr: "1,a,13", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "Parsing Error: strconv.Atoi: parsing \"a\": invalid syntax"},
-		{name: "FC: invalid special char", expr: "L", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: "Parsing Error: strconv.Atoi: parsing \"L\": invalid syntax"},
+		{name: "FC: out of bounds", expr: "1,4,13", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: &ParseError{Token: "13", Offset: 4, Err: ErrOutOfBounds}},
+		{name: "FC: chars in expr", expr: "1,a,13", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: &ParseError{Token: "a", Offset: 2, Err: ErrInvalidValue}},
+		{name: "FC: chars in range", expr: "1,2-x", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: &ParseError{Token: "x", Offset: 4, Err: ErrInvalidValue}},
+		{name: "FC: invalid special char", expr: "L", bounds: MonthBound, abbr: MONTH_ABBREVIATIONS, expected: &ParseError{Token: "L", Offset: 0, Err: ErrInvalidValue}},
 	}
 
 	for _, tc := range failureTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := parseField(tc.expr, tc.bounds, tc.abbr)
-			assertError(t, err, tc.expected)
+			_, _, err := parseField(tc.expr, tc.bounds, tc.abbr, newConfig(nil))
+			assertSuccess(t, err, tc.expected, nil)
 		})
 	}
 
@@ -118,10 +156,21 @@ func TestParseField(t *testing.T) {
 
 	for _, tc := range successTestCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := parseField(tc.expr, tc.bounds, tc.abbr)
+			got, _, err := parseField(tc.expr, tc.bounds, tc.abbr, newConfig(nil))
 			assertSuccess(t, got, tc.expected, err)
 		})
 	}
+
+	t.Run("SC: picked values in term order", func(t *testing.T) {
+		cfg := newConfig([]Option{WithRand(rand.New(rand.NewSource(1)))})
+		values, picked, err := parseField("30~39,50,0~9", MinuteBound, map[string]string{}, cfg)
+		assertSuccess(t, picked, []int{values[1], values[0]}, err)
+	})
+
+	t.Run("SC: no picked values without ~", func(t *testing.T) {
+		_, _, picked, err := parseDayField("1-5,L", FieldDayOfWeek, newConfig(nil))
+		assertSuccess(t, picked, []int(nil), err)
+	})
 }
 
 func TestParse(t *testing.T) {
@@ -131,21 +180,21 @@ func TestParse(t *testing.T) {
 		expected string
 	}{
 		{name: "FC: invalid number of cron fields", cronExpr: "* * * * /usr/bin/find", expected: "Validation Error: invalid number of cron fields"},
-		{name: "FC: space in comma separated field", cronExpr: "* * * 1, 12 * /usr/bin/find", expected: "Validation Error: invalid number of cron fields"},
-		{name: "FC: invalid cron field", cronExpr: "abc * * * * /usr/bin/find", expected: "Validation Error: invalid time field"},
-		{name: "FC: invalid special char", cronExpr: "* * * * ? /usr/bin/find", expected: "Validation Error: invalid time field"},
+		{name: "FC: space in comma separated field", cronExpr: "* * * 1, 12 * /usr/bin/find", expected: "Parsing Error: month field \",\": unexpected token"},
+		{name: "FC: invalid cron field", cronExpr: "abc * * * * /usr/bin/find", expected: "Parsing Error: minute field \"abc\": invalid value"},
+		{name: "FC: invalid special char", cronExpr: "* * * * X /usr/bin/find", expected: "Parsing Error: day of week field \"X\": invalid value"},
 
-		{name: "FC: invalid minute cron field", cronExpr: "2* * * * * /usr/bin/find", expected: "Parsing Error: strconv.Atoi: parsing \"2*\": invalid syntax"},
-		{name: "FC: invalid month cron field", cronExpr: "* * * * Monday /usr/bin/find", expected: "Parsing Error: strconv.Atoi: parsing \"MONDAY\": invalid syntax"},
+		{name: "FC: invalid minute cron field", cronExpr: "2* * * * * /usr/bin/find", expected: "Parsing Error: minute field \"*\": unexpected token"},
+		{name: "FC: invalid month cron field", cronExpr: "* * * * Mondays /usr/bin/find", expected: "Parsing Error: day of week field \"Mondays\": invalid value (did you mean \"MONDAY\"?)"},
 
-		{name: "FC: invalid regular instants 1", cronExpr: "*/100 * * * * /usr/bin/find", expected: "Parsing Error: invalid interval"},
-		{name: "FC: invalid regular instants 2", cronExpr: "* * * * */0 /usr/bin/find", expected: "Parsing Error: invalid interval"},
+		{name: "FC: invalid regular instants 1", cronExpr: "*/100 * * * * /usr/bin/find", expected: "Parsing Error: minute field \"*/100\": invalid interval (did you mean \"0\"?)"},
+		{name: "FC: invalid regular instants 2", cronExpr: "* * * * */0 /usr/bin/find", expected: "Parsing Error: day of week field \"*/0\": invalid interval (did you mean \"*\"?)"},
 
-		{name: "FC: invalid bound val", cronExpr: "* * ?-? * * /usr/bin/find", expected: "Parsing Error: strconv.Atoi: parsing \"?\": invalid syntax"},
-		{name: "FC: invalid bounds", cronExpr: "* * 0-32 * * /usr/bin/find", expected: "Parsing Error: invalid value, out of bounds"},
+		{name: "FC: invalid bound val", cronExpr: "* * ?-? * * /usr/bin/find", expected: "Parsing Error: day of month field \"?\": unexpected token"},
+		{name: "FC: invalid bounds", cronExpr: "* * 0-32 * * /usr/bin/find", expected: "Parsing Error: day of month field \"0-32\": invalid value, out of bounds"},
 
-		{name: "FC: invalid abbr interval", cronExpr: "* * * * Mon-Fri/8 /usr/bin/find", expected: "Parsing Error: invalid interval"},
-		{name: "FC: invalid comma separated field", cronExpr: "* * * 1,march * /usr/bin/find", expected: "Parsing Error: strconv.Atoi: parsing \"MARCH\": invalid syntax"},
+		{name: "FC: invalid abbr interval", cronExpr: "* * * * Mon-Fri/8 /usr/bin/find", expected: "Parsing Error: day of week field \"Mon-Fri/8\": invalid interval (did you mean \"MON\"?)"},
+		{name: "FC: invalid comma separated field", cronExpr: "* * * 1,marc * /usr/bin/find", expected: "Parsing Error: month field \"marc\": invalid value (did you mean \"MAR\"?)"},
 	}
 
 	for _, tc := range parseFailureTestCases {
@@ -179,3 +228,476 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseInLocation(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		loc      *time.Location
+		expected string
+	}{
+		{name: "FC: unknown zone", cronExpr: "CRON_TZ=Mars/Olympus_Mons * * * * * cmd", loc: time.UTC, expected: "Validation Error: invalid time zone"},
+		{name: "FC: prefix only", cronExpr: "TZ=America/New_York", loc: time.UTC, expected: "Validation Error: invalid number of cron fields"},
+		{name: "FC: nil location", cronExpr: "* * * * * cmd", loc: nil, expected: "Validation Error: invalid time zone"},
+		{name: "FC: invalid fields after prefix", cronExpr: "TZ=UTC * * * * cmd", loc: time.UTC, expected: "Validation Error: invalid number of cron fields"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseInLocation(tc.cronExpr, tc.loc)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		loc      *time.Location
+		expected *time.Location
+	}{
+		{name: "SC: default UTC", cronExpr: "* * * * * cmd", loc: time.UTC, expected: time.UTC},
+		{name: "SC: location argument", cronExpr: "* * * * * cmd", loc: newYork, expected: newYork},
+		{name: "SC: CRON_TZ prefix", cronExpr: "CRON_TZ=Asia/Kolkata * * * * * cmd", loc: time.UTC, expected: kolkata},
+		{name: "SC: TZ prefix overrides location", cronExpr: "TZ=Asia/Kolkata * * * * * cmd", loc: newYork, expected: kolkata},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseInLocation(tc.cronExpr, tc.loc)
+			assertSuccess(t, got.Location().String(), tc.expected.String(), err)
+		})
+	}
+
+	t.Run("SC: printed time zone", func(t *testing.T) {
+		got, err := Parse("CRON_TZ=Asia/Kolkata 30 4 1 1 0 cmd")
+		expected := "minute\t\t30\nhour\t\t4\nday of month\t1\nmonth\t\t1\nday of week\t0\ncommand\t\tcmd\ntime zone\tAsia/Kolkata"
+		assertSuccess(t, got.String(), expected, err)
+	})
+}
+
+func TestParseWithSeconds(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "FC: missing seconds field", cronExpr: "* * * * * cmd", expected: "Validation Error: invalid number of cron fields"},
+		{name: "FC: seconds out of bounds", cronExpr: "60 * * * * * cmd", expected: "Parsing Error: second field \"60\": invalid value, out of bounds (did you mean \"0\"?)"},
+		{name: "FC: invalid seconds interval", cronExpr: "*/61 * * * * * cmd", expected: "Parsing Error: second field \"*/61\": invalid interval (did you mean \"0\"?)"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr, WithSeconds())
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "SC: every 15 seconds", cronExpr: "*/15 30 4 1 1 0 cmd", expected: "second\t\t0 15 30 45\nminute\t\t30\nhour\t\t4\nday of month\t1\nmonth\t\t1\nday of week\t0\ncommand\t\tcmd"},
+		{name: "SC: seconds with abbr", cronExpr: "5,10-12 0 0 1 Jan-Feb Mon cmd", expected: "second\t\t5 10 11 12\nminute\t\t0\nhour\t\t0\nday of month\t1\nmonth\t\t1 2\nday of week\t1\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr, WithSeconds())
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+}
+
+func TestParseWithYear(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "FC: missing year field", cronExpr: "* * * * * cmd", expected: "Validation Error: invalid number of cron fields"},
+		{name: "FC: year out of bounds", cronExpr: "* * * * * 1969 cmd", expected: "Parsing Error: year field \"1969\": invalid value, out of bounds"},
+		{name: "FC: invalid year range", cronExpr: "* * * * * 2030-2020 cmd", expected: "Parsing Error: year field \"2030-2020\": invalid bounds"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr, WithYear())
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		opts     []Option
+		expected string
+	}{
+		{name: "SC: year range", cronExpr: "30 4 1 1 0 2024-2026 cmd", opts: []Option{WithYear()}, expected: "minute\t\t30\nhour\t\t4\nday of month\t1\nmonth\t\t1\nday of week\t0\nyear\t\t2024 2025 2026\ncommand\t\tcmd"},
+		{name: "SC: year interval", cronExpr: "30 4 1 1 0 */40 cmd", opts: []Option{WithYear()}, expected: "minute\t\t30\nhour\t\t4\nday of month\t1\nmonth\t\t1\nday of week\t0\nyear\t\t1970 2010 2050 2090\ncommand\t\tcmd"},
+		{name: "SC: quartz seven fields", cronExpr: "0 30 4 1 1 0 2030 cmd", opts: []Option{WithSeconds(), WithYear()}, expected: "second\t\t0\nminute\t\t30\nhour\t\t4\nday of month\t1\nmonth\t\t1\nday of week\t0\nyear\t\t2030\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr, tc.opts...)
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+}
+
+func TestMacroHandler(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "FC: unknown macro", cronExpr: "@fortnightly cmd", expected: "Validation Error: invalid macro"},
+		{name: "FC: missing space", cronExpr: "@dailycmd", expected: "Validation Error: invalid macro (did you mean \"@DAILY\"?)"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := handleMacro(tc.cronExpr, newConfig(nil))
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		opts     []Option
+		expected string
+	}{
+		{name: "SC: no macro", cronExpr: "0 0 * * * cmd", expected: "0 0 * * * cmd"},
+		{name: "SC: yearly", cronExpr: "@yearly cmd", expected: "0 0 1 1 * cmd"},
+		{name: "SC: annually", cronExpr: "@annually cmd", expected: "0 0 1 1 * cmd"},
+		{name: "SC: monthly", cronExpr: "@monthly cmd", expected: "0 0 1 * * cmd"},
+		{name: "SC: weekly", cronExpr: "@weekly cmd", expected: "0 0 * * 0 cmd"},
+		{name: "SC: daily", cronExpr: "@daily cmd", expected: "0 0 * * * cmd"},
+		{name: "SC: midnight", cronExpr: "@midnight cmd", expected: "0 0 * * * cmd"},
+		{name: "SC: hourly upper case", cronExpr: "@HOURLY cmd", expected: "0 * * * * cmd"},
+		{name: "SC: no command", cronExpr: "@daily", expected: "0 0 * * *"},
+		{name: "SC: with seconds and year", cronExpr: "@daily cmd", opts: []Option{WithSeconds(), WithYear()}, expected: "0 0 0 * * * * cmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := handleMacro(tc.cronExpr, newConfig(tc.opts))
+			assertSuccess(t, got, tc.expected, err)
+		})
+	}
+}
+
+func TestParseMacro(t *testing.T) {
+	t.Run("FC: macro without command", func(t *testing.T) {
+		_, err := Parse("@daily")
+		assertError(t, err, "Validation Error: invalid number of cron fields")
+	})
+
+	t.Run("SC: weekly", func(t *testing.T) {
+		got, err := Parse("@weekly /usr/bin/find")
+		expected := "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0\ncommand\t\t/usr/bin/find"
+		assertSuccess(t, got.String(), expected, err)
+	})
+
+	t.Run("SC: time zone and macro", func(t *testing.T) {
+		got, err := Parse("CRON_TZ=Asia/Kolkata @yearly cmd")
+		expected := "minute\t\t0\nhour\t\t0\nday of month\t1\nmonth\t\t1\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd\ntime zone\tAsia/Kolkata"
+		assertSuccess(t, got.String(), expected, err)
+	})
+}
+
+func TestRebootHandler(t *testing.T) {
+	rebootTestCases := []struct {
+		name        string
+		cronExpr    string
+		expectedCmd string
+		expectedOk  bool
+	}{
+		{name: "not a reboot", cronExpr: "@daily cmd", expectedCmd: "", expectedOk: false},
+		{name: "reboot", cronExpr: "@reboot /usr/bin/start --fast", expectedCmd: "/usr/bin/start --fast", expectedOk: true},
+		{name: "upper case", cronExpr: "@REBOOT cmd", expectedCmd: "cmd", expectedOk: true},
+	}
+
+	for _, tc := range rebootTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, ok, err := handleReboot(tc.cronExpr)
+			assertSuccess(t, cmd, tc.expectedCmd, err)
+			assertSuccess(t, ok, tc.expectedOk, err)
+		})
+	}
+
+	t.Run("missing command", func(t *testing.T) {
+		_, _, err := handleReboot("@reboot")
+		assertError(t, err, "Validation Error: invalid number of cron fields")
+	})
+}
+
+func TestDaySpecHandler(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		expr     string
+		field    FieldKind
+		expected string
+	}{
+		{name: "last day offset out of bounds", expr: "L-31", field: FieldDayOfMonth, expected: "invalid value, out of bounds"},
+		{name: "invalid last day offset", expr: "L-x", field: FieldDayOfMonth, expected: "invalid value"},
+		{name: "nearest weekday out of bounds", expr: "32W", field: FieldDayOfMonth, expected: "invalid value, out of bounds"},
+		{name: "last weekday of out of bounds", expr: "8L", field: FieldDayOfWeek, expected: "invalid value, out of bounds"},
+		{name: "nth out of bounds", expr: "5#6", field: FieldDayOfWeek, expected: "invalid value, out of bounds"},
+		{name: "nth weekday out of bounds", expr: "9#1", field: FieldDayOfWeek, expected: "invalid value, out of bounds"},
+		{name: "double hash", expr: "5#1#2", field: FieldDayOfWeek, expected: "invalid cron field"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := handleDaySpec(lexTerm(tc.expr), tc.field)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name         string
+		expr         string
+		field        FieldKind
+		expectedSpec *daySpec
+		expectedExpr string
+	}{
+		{name: "last day", expr: "L", field: FieldDayOfMonth, expectedSpec: &daySpec{kind: specLastDay}, expectedExpr: "L"},
+		{name: "days before last day", expr: "L-3", field: FieldDayOfMonth, expectedSpec: &daySpec{kind: specLastDay, value: 3}, expectedExpr: "L-3"},
+		{name: "last weekday", expr: "LW", field: FieldDayOfMonth, expectedSpec: &daySpec{kind: specLastWeekday}, expectedExpr: "LW"},
+		{name: "nearest weekday", expr: "15W", field: FieldDayOfMonth, expectedSpec: &daySpec{kind: specNearestWeekday, value: 15}, expectedExpr: "15W"},
+		{name: "last friday", expr: "5L", field: FieldDayOfWeek, expectedSpec: &daySpec{kind: specLastOfWeekday, value: 5}, expectedExpr: "5L"},
+		{name: "last friday abbr", expr: "FRIL", field: FieldDayOfWeek, expectedSpec: &daySpec{kind: specLastOfWeekday, value: 5}, expectedExpr: "FRIL"},
+		{name: "third friday", expr: "FRI#3", field: FieldDayOfWeek, expectedSpec: &daySpec{kind: specNthOfWeekday, value: 5, nth: 3}, expectedExpr: "FRI#3"},
+		{name: "weekdays counted from sunday as 0", expr: "6#3", field: FieldDayOfWeek, expectedSpec: &daySpec{kind: specNthOfWeekday, value: 6, nth: 3}, expectedExpr: "6#3"},
+		{name: "plain L is saturday", expr: "L", field: FieldDayOfWeek, expectedSpec: nil, expectedExpr: "6"},
+		{name: "not a day spec", expr: "1-5", field: FieldDayOfWeek, expectedSpec: nil, expectedExpr: "1-5"},
+		{name: "W only in day of month", expr: "15W", field: FieldMonth, expectedSpec: nil, expectedExpr: "15W"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, term, err := handleDaySpec(lexTerm(tc.expr), tc.field)
+			assertSuccess(t, spec, tc.expectedSpec, err)
+			assertSuccess(t, joinTokens(term), tc.expectedExpr, err)
+		})
+	}
+}
+
+func TestParseQuartzDayFields(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "FC: question mark outside day fields", cronExpr: "? * * * * cmd", expected: "Parsing Error: minute field \"?\": unexpected token"},
+		{name: "FC: question mark in a list", cronExpr: "* * ?,1 * * cmd", expected: "Parsing Error: day of month field \"?\": unexpected token"},
+		{name: "FC: L in month", cronExpr: "* * * L * cmd", expected: "Parsing Error: month field \"L\": invalid value"},
+		{name: "FC: invalid nth", cronExpr: "* * ? * 5#0 cmd", expected: "Parsing Error: day of week field \"5#0\": invalid value, out of bounds"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr)
+			assertError(t, err, tc.expected)
+		})
+	}
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "SC: third friday", cronExpr: "0 12 ? * 5#3 cmd", expected: "minute\t\t0\nhour\t\t12\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t5#3\ncommand\t\tcmd"},
+		{name: "SC: last weekday", cronExpr: "0 0 LW * ? cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\tLW\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd"},
+		{name: "SC: values and specs", cronExpr: "0 0 1,15W,L-2 * * cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 15W L-2\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd"},
+		{name: "SC: plain L is saturday", cronExpr: "0 0 ? * 1,L cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t1 6\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr)
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+
+	t.Run("SC: question mark is a wildcard", func(t *testing.T) {
+		got, err := Parse("0 12 ? * 5#3 cmd")
+		assertSuccess(t, got.IsWildcard(FieldDayOfMonth), true, err)
+	})
+}
+
+func TestParseHash(t *testing.T) {
+	t.Run("FC: H without hash key", func(t *testing.T) {
+		_, err := Parse("H * * * * cmd")
+		assertError(t, err, "Parsing Error: minute field \"H\": H needs a hash key")
+	})
+
+	successTestCases := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{name: "SC: one key", key: "nightly-backup", expected: "minute\t\t23\nhour\t\t5\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t1 3 5\ncommand\t\tcmd"},
+		{name: "SC: another key", key: "report", expected: "minute\t\t35\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t1 3 5\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse("H h(0-5) * * H/2 cmd", WithHashKey(tc.key))
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+
+	t.Run("SC: same key, same schedule", func(t *testing.T) {
+		first, _ := Parse("H H * * * cmd", WithHashKey("report"))
+		second, err := Parse("H H * * * cmd", WithHashKey("report"))
+		assertSuccess(t, second.String(), first.String(), err)
+	})
+}
+
+func TestParseRandom(t *testing.T) {
+	t.Run("FC: out of bounds", func(t *testing.T) {
+		_, err := Parse("0~60 * * * * cmd")
+		assertError(t, err, "Parsing Error: minute field \"0~60\": invalid value, out of bounds")
+	})
+
+	t.Run("SC: reproducible with a given source", func(t *testing.T) {
+		got, err := Parse("0~30 ~ * * 1,3~ cmd", WithRand(rand.New(rand.NewSource(1))))
+		expected := "minute\t\t27\nhour\t\t15\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t1 6\ncommand\t\tcmd"
+		assertSuccess(t, got.String(), expected, err)
+	})
+}
+
+func TestParseWrapAround(t *testing.T) {
+	t.Run("FC: without the option", func(t *testing.T) {
+		_, err := Parse("0 22-2 * * * cmd")
+		assertError(t, err, "Parsing Error: hour field \"22-2\": invalid bounds")
+	})
+
+	t.Run("FC: from seven without the option", func(t *testing.T) {
+		_, err := Parse("0 0 * * 7-2 cmd")
+		assertError(t, err, "Parsing Error: day of week field \"7-2\": invalid bounds")
+	})
+
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "SC: hours", cronExpr: "0 22-2 * * * cmd", expected: "minute\t\t0\nhour\t\t0 1 2 22 23\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd"},
+		{name: "SC: hours with step", cronExpr: "0 22-2/2 * * * cmd", expected: "minute\t\t0\nhour\t\t0 2 22\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd"},
+		{name: "SC: abbreviations", cronExpr: "0 0 * Dec-Jan FRI-MON cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 12\nday of week\t0 1 5 6\ncommand\t\tcmd"},
+		{name: "SC: day of month", cronExpr: "0 0 30-2 * * cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tcmd"},
+		{name: "SC: day of week from seven", cronExpr: "0 0 * * 7-2 cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr, WithWrapAround())
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+}
+
+func TestParseStartStep(t *testing.T) {
+	t.Run("FC: start out of bounds", func(t *testing.T) {
+		_, err := Parse("60/15 * * * * cmd")
+		assertError(t, err, "Parsing Error: minute field \"60/15\": invalid value, out of bounds")
+	})
+
+	t.Run("SC: every field", func(t *testing.T) {
+		got, err := Parse("10/20 5/15 3/6 20/5 Mar/4 MON/2 2090/4 cmd", WithSeconds(), WithYear())
+		expected := "second\t\t10 30 50\nminute\t\t5 20 35 50\nhour\t\t3 9 15 21\nday of month\t20 25 30\nmonth\t\t3 7 11\nday of week\t1 3 5\nyear\t\t2090 2094 2098\ncommand\t\tcmd"
+		assertSuccess(t, got.String(), expected, err)
+	})
+}
+
+func TestParseSundayAlias(t *testing.T) {
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "SC: range to seven", cronExpr: "0 0 * * Fri-7 cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 5 6\ncommand\t\tcmd"},
+		{name: "SC: nth and last sunday", cronExpr: "0 0 ? * 7#2,7L cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0#2 0L\ncommand\t\tcmd"},
+		{name: "SC: full names", cronExpr: "0 0 * september,December Sunday cmd", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t9 12\nday of week\t0\ncommand\t\tcmd"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr)
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+
+	t.Run("SC: seven in random and hashed ranges", func(t *testing.T) {
+		got, err := Parse("0 0 * * 7~7,H(7-7) cmd", WithHashKey("job"))
+		assertSuccess(t, got.dow, []int{0}, err)
+
+		for i := 0; i < 20; i++ {
+			got, err := Parse("0 0 * * 5~7,H(5-7) cmd", WithHashKey("job"))
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			for _, day := range got.dow {
+				if day != 0 && day != 5 && day != 6 {
+					t.Errorf("expected days in 5-7 but got %v", got.dow)
+				}
+			}
+		}
+	})
+}
+
+func TestCommandHandler(t *testing.T) {
+	commandTestCases := []struct {
+		name          string
+		cmdExpr       string
+		expectedCmd   string
+		expectedStdin string
+	}{
+		{name: "plain command", cmdExpr: "/usr/bin/find /tmp -mtime +7", expectedCmd: "/usr/bin/find /tmp -mtime +7", expectedStdin: ""},
+		{name: "stdin", cmdExpr: "mail -s hi root%hello%world", expectedCmd: "mail -s hi root", expectedStdin: "hello\nworld"},
+		{name: "escaped percent", cmdExpr: "date +\\%Y-\\%m%today", expectedCmd: "date +%Y-%m", expectedStdin: "today"},
+		{name: "empty stdin", cmdExpr: "cat%", expectedCmd: "cat", expectedStdin: ""},
+	}
+
+	for _, tc := range commandTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, stdin := handleCommand(tc.cmdExpr)
+			assertSuccess(t, []string{cmd, stdin}, []string{tc.expectedCmd, tc.expectedStdin}, nil)
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	successTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "SC: tabs and arguments", cronExpr: "0\t5 *  * *\t/usr/bin/find /tmp -mtime +7", expected: "minute\t\t0\nhour\t\t5\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\t/usr/bin/find /tmp -mtime +7"},
+		{name: "SC: stdin", cronExpr: "@reboot mail root%up%again", expected: "event\t\t@reboot\ncommand\t\tmail root\nstdin\t\tup\n\t\tagain"},
+		{name: "SC: macro with arguments", cronExpr: "@daily  backup  --full", expected: "minute\t\t0\nhour\t\t0\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\tbackup  --full"},
+		{name: "SC: time zone with arguments", cronExpr: "CRON_TZ=UTC\t0 5 * * * echo a b", expected: "minute\t\t0\nhour\t\t5\nday of month\t1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28 29 30 31\nmonth\t\t1 2 3 4 5 6 7 8 9 10 11 12\nday of week\t0 1 2 3 4 5 6\ncommand\t\techo a b"},
+	}
+
+	for _, tc := range successTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.cronExpr)
+			assertSuccess(t, got.String(), tc.expected, err)
+		})
+	}
+
+	t.Run("SC: command and stdin", func(t *testing.T) {
+		got, err := Parse("0 5 * * * mail -s report root%see attached")
+		assertSuccess(t, []string{got.Command(), got.Stdin()}, []string{"mail -s report root", "see attached"}, err)
+	})
+}
@@ -1,20 +1,460 @@
 package cronparser
 
-import "fmt"
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds how far ahead Next looks before it decides that a
+// schedule without a year field never fires. Calendars repeat every 400 years, so any
+// day-of-month/day-of-week combination that exists shows up within it.
+const searchYears = 400
 
 type Schedule struct {
+	kind                          Kind
+	second                        []int //nil unless parsed WithSeconds
 	minute, hour, dom, month, dow []int
+	year                          []int //nil unless parsed WithYear
+	domSpecs, dowSpecs            []daySpec
 	cmd                           string
+	stdin                         string //input after the first % of the command
+	location                      *time.Location
+	dstPolicy                     DSTPolicy
+	dayMatch                      DayMatch
+	wildcard                      map[FieldKind]bool  //fields written with a leading asterisk
+	random                        map[FieldKind]bool  //fields whose value was picked with ~
+	picked                        map[FieldKind][]int //the values picked with ~, in the order of their terms
+	fields                        []Field             //the time fields as written
+}
+
+// Kind tells calendar schedules apart from event-triggered ones.
+type Kind int
+
+const (
+	// KindCalendar fires at the times its fields describe.
+	KindCalendar Kind = iota
+
+	// KindReboot fires once when the cron daemon starts (@reboot) and has no
+	// calendar occurrences.
+	KindReboot
+)
+
+func (k Kind) String() string {
+	if k == KindReboot {
+		return "reboot"
+	}
+
+	return "calendar"
 }
 
+// DayMatch decides how day of month and day of week combine when neither
+// field is a wildcard.
+type DayMatch int
+
+const (
+	// DayMatchOr fires when either day field matches, as in Vixie cron.
+	// It is the default.
+	DayMatchOr DayMatch = iota
+
+	// DayMatchAnd fires only when both day fields match.
+	DayMatchAnd
+)
+
 func (s Schedule) String() string {
-	outputFormat := "minute\t\t%s\nhour\t\t%s\nday of month\t%s\nmonth\t\t%s\nday of week\t%s\ncommand\t\t%s"
-	minuteString := intsJoin(s.minute, " ")
-	hourString := intsJoin(s.hour, " ")
-	domString := intsJoin(s.dom, " ")
-	monthString := intsJoin(s.month, " ")
-	dowString := intsJoin(s.dow, " ")
-	cmdString := s.cmd
-
-	return fmt.Sprintf(outputFormat, minuteString, hourString, domString, monthString, dowString, cmdString)
+	if s.kind == KindReboot {
+		return "event\t\t@reboot\n" + commandLines(s.cmd, s.stdin)
+	}
+
+	var lines []string
+	if s.second != nil {
+		lines = append(lines, "second\t\t"+intsJoin(s.second, " "))
+	}
+
+	lines = append(lines,
+		"minute\t\t"+intsJoin(s.minute, " "),
+		"hour\t\t"+intsJoin(s.hour, " "),
+		"day of month\t"+dayFieldJoin(s.dom, s.domSpecs),
+		"month\t\t"+intsJoin(s.month, " "),
+		"day of week\t"+dayFieldJoin(s.dow, s.dowSpecs))
+
+	if s.year != nil {
+		lines = append(lines, "year\t\t"+intsJoin(s.year, " "))
+	}
+
+	lines = append(lines, commandLines(s.cmd, s.stdin))
+
+	if s.loc() != time.UTC {
+		lines = append(lines, "time zone\t"+s.loc().String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Kind returns whether the schedule is calendar based or event-triggered.
+// The time computations of an event-triggered schedule find no occurrences.
+func (s Schedule) Kind() Kind {
+	return s.kind
+}
+
+// Command returns the command to run, with its arguments.
+func (s Schedule) Command() string {
+	return s.cmd
+}
+
+// Stdin returns the standard input given to the command after a %, with each
+// further % turned into a newline, or "" when there is none.
+func (s Schedule) Stdin() string {
+	return s.stdin
+}
+
+// IsWildcard reports whether field was written with a leading asterisk,
+// e.g. "*" or "*/15", rather than restricted to specific values.
+func (s Schedule) IsWildcard(field FieldKind) bool {
+	return s.wildcard[field]
+}
+
+// IsRandom reports whether field's value was picked at random from a range
+// written with "~", e.g. "0~30", when the schedule was parsed.
+func (s Schedule) IsRandom(field FieldKind) bool {
+	return s.random[field]
+}
+
+// Fields returns the time fields as written, in the order they appear in the
+// expression, or nil for an @reboot schedule. The fields are a copy, so changing
+// them leaves the schedule as it is.
+func (s Schedule) Fields() []Field {
+	if s.fields == nil {
+		return nil
+	}
+
+	fields := make([]Field, len(s.fields))
+	for i, field := range s.fields {
+		fields[i] = field.copy()
+	}
+
+	return fields
+}
+
+// Expression renders the schedule back into cron text in canonical form:
+// single spaces between fields, names upper cased, numbers without leading
+// zeros and macros expanded, e.g. "0 9 * * MON-FRI cmd" for "00  9 * * mon-fri cmd".
+// A value written with "~" is given as the value picked for it. A time zone
+// other than UTC is given as a CRON_TZ prefix when time.LoadLocation can load
+// it by name; others, such as a time.FixedZone, are left for ParseInLocation.
+//
+// Parsing the expression again, with the same options, gives the same
+// schedule, apart from IsRandom as its values are no longer picked.
+func (s Schedule) Expression() string {
+	var prefix string
+	if loc := s.loc(); loc != time.UTC {
+		if _, err := time.LoadLocation(loc.String()); err == nil {
+			prefix = TIME_ZONE_PREFIXES[0] + loc.String() + " "
+		}
+	}
+
+	if s.kind == KindReboot {
+		return prefix + "@reboot " + commandExpr(s.cmd, s.stdin)
+	}
+
+	exprList := make([]string, 0, len(s.fields)+1)
+	for _, field := range s.fields {
+		picked := s.picked[field.Kind]
+
+		terms := make([]string, len(field.Terms))
+		for i, term := range field.Terms {
+			terms[i] = term.String()
+			if term.Kind == TermRandom && len(picked) > 0 {
+				terms[i], picked = strconv.Itoa(picked[0]), picked[1:]
+			}
+		}
+
+		exprList = append(exprList, strings.Join(terms, ","))
+	}
+
+	return prefix + strings.Join(append(exprList, commandExpr(s.cmd, s.stdin)), " ")
+}
+
+// Location returns the time zone the schedule's fields are interpreted in.
+func (s Schedule) Location() *time.Location {
+	return s.loc()
+}
+
+func (s Schedule) loc() *time.Location {
+	if s.location == nil {
+		return time.UTC
+	}
+
+	return s.location
+}
+
+// Next returns the first activation strictly after from, in the schedule's location.
+// It returns the zero time.Time if the schedule never fires, e.g. "0 0 30 2 *",
+// or is not calendar based, e.g. "@reboot".
+func (s Schedule) Next(from time.Time) time.Time {
+	var next time.Time
+	s.visitActivations(from, true, func(t time.Time) bool {
+		next = t
+		return false
+	})
+
+	return next
+}
+
+// Prev returns the latest activation strictly before from, in the schedule's location.
+// It returns the zero time.Time if the schedule never fires or is not calendar based.
+func (s Schedule) Prev(from time.Time) time.Time {
+	var prev time.Time
+	s.visitActivations(from, false, func(t time.Time) bool {
+		prev = t
+		return false
+	})
+
+	return prev
+}
+
+// Matches reports whether the schedule fires at t, to the minute (or to the
+// second with a seconds field), in the schedule's location.
+// It only looks up the expanded fields, so it is cheap enough for tick loops.
+func (s Schedule) Matches(t time.Time) bool {
+	if s.kind != KindCalendar {
+		return false
+	}
+
+	t = t.In(s.loc())
+	resolution := s.resolution()
+	wall := civil(t).Truncate(resolution)
+
+	if s.matchesWall(wall) && firesAt(s.activations(wall), t, resolution) {
+		return true
+	}
+
+	// the clocks may have just jumped forward over matching times
+	for skipped := civil(t.Add(-resolution)).Truncate(resolution).Add(resolution); skipped.Before(wall); skipped = skipped.Add(resolution) {
+		if s.matchesWall(skipped) && firesAt(s.activations(skipped), t, resolution) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Between lists the activations in [start, end), in the schedule's location and in order.
+// A positive limit caps the number of activations returned.
+func (s Schedule) Between(start, end time.Time, limit int) []time.Time {
+	var activations []time.Time
+	s.visitActivations(start.Add(-time.Nanosecond), true, func(t time.Time) bool {
+		if !t.Before(end) || (limit > 0 && len(activations) == limit) {
+			return false
+		}
+
+		activations = append(activations, t)
+		return true
+	})
+
+	return activations
+}
+
+// visitActivations visits the activations strictly after from (forward) or
+// strictly before it, in search order, until visit returns false.
+//
+// The activations of a wall clock time never come before those of the wall
+// clock times walked ahead of it, except for the second occurrence of a time
+// repeated by a transition. Those are held back until their turn.
+func (s Schedule) visitActivations(from time.Time, forward bool, visit func(time.Time) bool) {
+	if s.kind != KindCalendar {
+		return
+	}
+
+	from = from.In(s.loc())
+	start := civil(from).Truncate(time.Second).Add(repeatedSpan(from, forward))
+
+	var last time.Time
+	emit := func(t time.Time) bool {
+		if !ahead(t, from, forward) || t.Equal(last) {
+			return true
+		}
+
+		last = t
+		return visit(t)
+	}
+
+	var heldBack []time.Time
+	stopped := false
+	s.walk(start, forward, func(wall time.Time) bool {
+		instants := s.activations(wall)
+		if len(instants) == 0 {
+			return true
+		}
+
+		if !forward {
+			instants = reversedTimes(instants)
+		}
+
+		for len(heldBack) > 0 && !ahead(heldBack[0], instants[0], forward) {
+			if stopped = !emit(heldBack[0]); stopped {
+				return false
+			}
+
+			heldBack = heldBack[1:]
+		}
+
+		if stopped = !emit(instants[0]); stopped {
+			return false
+		}
+
+		if len(instants) > 1 {
+			heldBack = append(heldBack, instants[1:]...)
+			sort.Slice(heldBack, func(i, j int) bool {
+				return ahead(heldBack[j], heldBack[i], forward)
+			})
+		}
+
+		return true
+	})
+
+	for _, t := range heldBack {
+		if stopped || !emit(t) {
+			return
+		}
+	}
+}
+
+// walk visits the matching wall clock times from start onwards (forward) or backwards,
+// jumping from the largest unit to the smallest, until visit returns false
+// or searchYears have been covered.
+func (s Schedule) walk(start time.Time, forward bool, visit func(time.Time) bool) {
+	startYear, startMonth, startDay := start.Date()
+
+	yearStep := 1
+	if !forward {
+		yearStep = -1
+	}
+
+	lastYear := s.lastYear(startYear, forward)
+	for year := startYear; !precedes(lastYear, year, forward); year += yearStep {
+		if s.year != nil && !containsInt(s.year, year) {
+			continue
+		}
+
+		sameYear := year == startYear
+		for _, month := range ordered(s.month, forward) {
+			if sameYear && precedes(month, int(startMonth), forward) {
+				continue
+			}
+
+			sameMonth := sameYear && month == int(startMonth)
+			for _, day := range ordered(s.days(year, time.Month(month)), forward) {
+				if sameMonth && precedes(day, startDay, forward) {
+					continue
+				}
+
+				sameDay := sameMonth && day == startDay
+				for _, hour := range ordered(s.hour, forward) {
+					if sameDay && precedes(hour, start.Hour(), forward) {
+						continue
+					}
+
+					sameHour := sameDay && hour == start.Hour()
+					for _, minute := range ordered(s.minute, forward) {
+						if sameHour && precedes(minute, start.Minute(), forward) {
+							continue
+						}
+
+						sameMinute := sameHour && minute == start.Minute()
+						for _, second := range ordered(s.seconds(), forward) {
+							if sameMinute && precedes(second, start.Second(), forward) {
+								continue
+							}
+
+							if !visit(time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)) {
+								return
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// lastYear is the last year walk has to look at from startYear: the end of the
+// year field if there is one, searchYears away otherwise. It precedes startYear
+// when the year field lies entirely behind it.
+func (s Schedule) lastYear(startYear int, forward bool) int {
+	if s.year == nil {
+		if forward {
+			return startYear + searchYears
+		}
+
+		return startYear - searchYears
+	}
+
+	if forward {
+		return s.year[len(s.year)-1]
+	}
+
+	return s.year[0]
+}
+
+// days lists the days of the given month that match dom and dow, once their
+// month dependent values are resolved.
+func (s Schedule) days(year int, month time.Month) []int {
+	firstWeekday := int(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday())
+	domDays := resolveDaySpecs(s.domSpecs, year, month)
+	dowDays := resolveDaySpecs(s.dowSpecs, year, month)
+
+	var days []int
+	for day := 1; day <= daysIn(year, month); day++ {
+		if s.matchesDay(day, (firstWeekday+day-1)%7, domDays, dowDays) {
+			days = append(days, day)
+		}
+	}
+
+	return days
+}
+
+// seconds returns the expanded seconds, or just the start of the minute for
+// schedules without a seconds field.
+func (s Schedule) seconds() []int {
+	if s.second == nil {
+		return []int{0}
+	}
+
+	return s.second
+}
+
+// resolution is the smallest step between two activations.
+func (s Schedule) resolution() time.Duration {
+	if s.second == nil {
+		return time.Minute
+	}
+
+	return time.Second
+}
+
+func (s Schedule) matchesWall(wall time.Time) bool {
+	return containsInt(s.seconds(), wall.Second()) &&
+		containsInt(s.minute, wall.Minute()) &&
+		containsInt(s.hour, wall.Hour()) &&
+		containsInt(s.month, int(wall.Month())) &&
+		(s.year == nil || containsInt(s.year, wall.Year())) &&
+		s.matchesDay(wall.Day(), int(wall.Weekday()),
+			resolveDaySpecs(s.domSpecs, wall.Year(), wall.Month()),
+			resolveDaySpecs(s.dowSpecs, wall.Year(), wall.Month()))
+}
+
+// matchesDay follows Vixie cron: when both day fields are restricted, either
+// one matching is enough unless the schedule asks for DayMatchAnd.
+// domDays and dowDays are the days the daySpecs resolve to in the month.
+func (s Schedule) matchesDay(day, weekday int, domDays, dowDays []int) bool {
+	domMatch := containsInt(s.dom, day) || containsInt(domDays, day)
+	dowMatch := containsInt(s.dow, weekday) || containsInt(dowDays, day)
+
+	if s.dayMatch == DayMatchOr && !s.IsWildcard(FieldDayOfMonth) && !s.IsWildcard(FieldDayOfWeek) {
+		return domMatch || dowMatch
+	}
+
+	return domMatch && dowMatch
 }
@@ -1,6 +1,10 @@
 package cronparser
 
-import "testing"
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
 
 func TestString(t *testing.T) {
 	schedule := &Schedule{minute: []int{30}, hour: []int{4}, dom: []int{1}, month: []int{1}, dow: []int{0}, cmd: "cmd"}
@@ -11,3 +15,395 @@ func TestString(t *testing.T) {
 		t.Errorf("expected %s, but got %s", expected, got)
 	}
 }
+
+func TestNext(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 23, 59, 30, 0, time.UTC)
+
+	nextTestCases := []struct {
+		name     string
+		cronExpr string
+		from     time.Time
+		expected time.Time
+	}{
+		{name: "every minute", cronExpr: "* * * * * cmd", from: from, expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "strictly after from", cronExpr: "0 0 1 2 * cmd", from: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "later the same hour", cronExpr: "*/15 * * * * cmd", from: time.Date(2024, time.May, 5, 10, 16, 0, 0, time.UTC), expected: time.Date(2024, time.May, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "next hour", cronExpr: "5 */6 * * * cmd", from: time.Date(2024, time.May, 5, 6, 5, 0, 0, time.UTC), expected: time.Date(2024, time.May, 5, 12, 5, 0, 0, time.UTC)},
+		{name: "skips short months", cronExpr: "0 12 31 * * cmd", from: from, expected: time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "leap day", cronExpr: "0 0 29 2 * cmd", from: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "weekdays only", cronExpr: "30 9 * * Mon-Fri cmd", from: time.Date(2024, time.May, 3, 10, 0, 0, 0, time.UTC), expected: time.Date(2024, time.May, 6, 9, 30, 0, 0, time.UTC)},
+		{name: "next year", cronExpr: "59 23 24,31 12 * greetings", from: time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC), expected: time.Date(2025, time.December, 24, 23, 59, 0, 0, time.UTC)},
+		{name: "converts from to UTC", cronExpr: "0 * * * * cmd", from: time.Date(2024, time.May, 5, 10, 15, 0, 0, time.FixedZone("IST", 19800)), expected: time.Date(2024, time.May, 5, 5, 0, 0, 0, time.UTC)},
+		{name: "never fires", cronExpr: "0 0 30 2 * cmd", from: from, expected: time.Time{}},
+	}
+
+	for _, tc := range nextTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr)
+			assertSuccess(t, schedule.Next(tc.from), tc.expected, err)
+		})
+	}
+}
+
+func TestPrev(t *testing.T) {
+	from := time.Date(2024, time.February, 1, 0, 0, 30, 0, time.UTC)
+
+	prevTestCases := []struct {
+		name     string
+		cronExpr string
+		from     time.Time
+		expected time.Time
+	}{
+		{name: "every minute", cronExpr: "* * * * * cmd", from: from, expected: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "strictly before from", cronExpr: "0 0 1 2 * cmd", from: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "earlier the same hour", cronExpr: "*/15 * * * * cmd", from: time.Date(2024, time.May, 5, 10, 44, 0, 0, time.UTC), expected: time.Date(2024, time.May, 5, 10, 30, 0, 0, time.UTC)},
+		{name: "previous day", cronExpr: "30 9 * * * cmd", from: time.Date(2024, time.May, 5, 9, 29, 0, 0, time.UTC), expected: time.Date(2024, time.May, 4, 9, 30, 0, 0, time.UTC)},
+		{name: "skips short months", cronExpr: "0 12 31 * * cmd", from: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, time.May, 31, 12, 0, 0, 0, time.UTC)},
+		{name: "leap day", cronExpr: "0 0 29 2 * cmd", from: time.Date(2028, time.February, 1, 0, 0, 0, 0, time.UTC), expected: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "weekdays only", cronExpr: "30 9 * * Mon-Fri cmd", from: time.Date(2024, time.May, 6, 9, 0, 0, 0, time.UTC), expected: time.Date(2024, time.May, 3, 9, 30, 0, 0, time.UTC)},
+		{name: "previous year", cronExpr: "59 23 24,31 12 * greetings", from: time.Date(2024, time.December, 24, 23, 59, 0, 0, time.UTC), expected: time.Date(2023, time.December, 31, 23, 59, 0, 0, time.UTC)},
+		{name: "never fires", cronExpr: "0 0 31 4 * cmd", from: from, expected: time.Time{}},
+	}
+
+	for _, tc := range prevTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr)
+			assertSuccess(t, schedule.Prev(tc.from), tc.expected, err)
+		})
+	}
+
+	t.Run("agrees with Next", func(t *testing.T) {
+		for _, cronExpr := range []string{"* * * * * cmd", "*/7 3-5 */3 * Mon,Fri cmd", "0 0 29 2 * cmd", "30 4 1,15 * * cmd"} {
+			schedule, err := Parse(cronExpr)
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			next := schedule.Next(from)
+			if got := schedule.Prev(next.Add(time.Minute)); !got.Equal(next) {
+				t.Errorf("%s: expected %v, but got %v", cronExpr, next, got)
+			}
+
+			prev := schedule.Prev(from)
+			if got := schedule.Next(prev); !got.After(prev) || got.Before(from) {
+				t.Errorf("%s: expected the first activation after %v, but got %v", cronExpr, from, got)
+			}
+		}
+	})
+}
+
+func TestBetween(t *testing.T) {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	betweenTestCases := []struct {
+		name     string
+		cronExpr string
+		end      time.Time
+		limit    int
+		expected []time.Time
+	}{
+		{name: "includes start", cronExpr: "0 * * * * cmd", end: start.Add(3 * time.Hour), expected: []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)}},
+		{name: "limit", cronExpr: "*/15 * * * * cmd", end: start.AddDate(1, 0, 0), limit: 2, expected: []time.Time{start, start.Add(15 * time.Minute)}},
+		{name: "quarterly", cronExpr: "30 4 1 */3 * cmd", end: start.AddDate(1, 0, 0), expected: []time.Time{
+			time.Date(2024, time.January, 1, 4, 30, 0, 0, time.UTC),
+			time.Date(2024, time.April, 1, 4, 30, 0, 0, time.UTC),
+			time.Date(2024, time.July, 1, 4, 30, 0, 0, time.UTC),
+			time.Date(2024, time.October, 1, 4, 30, 0, 0, time.UTC),
+		}},
+		{name: "empty window", cronExpr: "0 0 1 1 * cmd", end: start, expected: nil},
+		{name: "never fires", cronExpr: "0 0 30 2 * cmd", end: start.AddDate(1, 0, 0), expected: nil},
+	}
+
+	for _, tc := range betweenTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr)
+			assertSuccess(t, schedule.Between(start, tc.end, tc.limit), tc.expected, err)
+		})
+	}
+
+	t.Run("every minute of a year", func(t *testing.T) {
+		schedule, err := Parse("* * * * * cmd")
+		got := schedule.Between(start, start.AddDate(1, 0, 0), 0)
+		assertSuccess(t, len(got), 366*24*60, err)
+	})
+}
+
+func TestMatches(t *testing.T) {
+	matchesTestCases := []struct {
+		name     string
+		cronExpr string
+		at       time.Time
+		expected bool
+	}{
+		{name: "every minute", cronExpr: "* * * * * cmd", at: time.Date(2024, time.May, 5, 10, 16, 42, 0, time.UTC), expected: true},
+		{name: "ignores seconds", cronExpr: "30 4 1,15 * * cmd", at: time.Date(2024, time.May, 15, 4, 30, 59, 0, time.UTC), expected: true},
+		{name: "wrong minute", cronExpr: "30 4 1,15 * * cmd", at: time.Date(2024, time.May, 15, 4, 31, 0, 0, time.UTC), expected: false},
+		{name: "wrong hour", cronExpr: "30 4 1,15 * * cmd", at: time.Date(2024, time.May, 15, 5, 30, 0, 0, time.UTC), expected: false},
+		{name: "wrong day of month", cronExpr: "30 4 1,15 * * cmd", at: time.Date(2024, time.May, 14, 4, 30, 0, 0, time.UTC), expected: false},
+		{name: "wrong month", cronExpr: "0 0 * Jan-Mar * cmd", at: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC), expected: false},
+		{name: "wrong day of week", cronExpr: "0 9 * * Mon-Fri cmd", at: time.Date(2024, time.May, 4, 9, 0, 0, 0, time.UTC), expected: false},
+		{name: "converts to UTC", cronExpr: "0 5 * * * cmd", at: time.Date(2024, time.May, 5, 10, 30, 0, 0, time.FixedZone("IST", 19800)), expected: true},
+	}
+
+	for _, tc := range matchesTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr)
+			assertSuccess(t, schedule.Matches(tc.at), tc.expected, err)
+		})
+	}
+
+	t.Run("agrees with Next", func(t *testing.T) {
+		schedule, err := Parse("*/7 3-5 */3 * Mon,Fri cmd")
+		next := schedule.Next(time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC))
+		assertSuccess(t, schedule.Matches(next) && !schedule.Matches(next.Add(-time.Minute)), true, err)
+	})
+}
+
+func TestLocation(t *testing.T) {
+	kolkata, _ := time.LoadLocation("Asia/Kolkata")
+	schedule, err := ParseInLocation("30 9 * * Mon-Fri cmd", kolkata)
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	from := time.Date(2024, time.May, 3, 3, 0, 0, 0, time.UTC) // 08:30 IST on a Friday
+	nextExpected := time.Date(2024, time.May, 3, 9, 30, 0, 0, kolkata)
+	prevExpected := time.Date(2024, time.May, 2, 9, 30, 0, 0, kolkata)
+
+	t.Run("next", func(t *testing.T) {
+		got := schedule.Next(from)
+		assertSuccess(t, got.Equal(nextExpected) && got.Location() == kolkata, true, nil)
+	})
+
+	t.Run("prev", func(t *testing.T) {
+		assertSuccess(t, schedule.Prev(from).Equal(prevExpected), true, nil)
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		assertSuccess(t, schedule.Matches(nextExpected.UTC()), true, nil)
+	})
+
+	t.Run("between", func(t *testing.T) {
+		got := schedule.Between(from, from.AddDate(0, 0, 4), 0)
+		assertSuccess(t, len(got), 2, nil)
+	})
+}
+
+func TestDayMatch(t *testing.T) {
+	from := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+
+	dayMatchTestCases := []struct {
+		name     string
+		cronExpr string
+		dayMatch DayMatch
+		expected time.Time
+	}{
+		{name: "either day field", cronExpr: "0 0 1,15 * Mon cmd", dayMatch: DayMatchOr, expected: time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "both day fields", cronExpr: "0 0 1,15 * Mon cmd", dayMatch: DayMatchAnd, expected: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "wildcard day of month", cronExpr: "0 0 * * Mon cmd", dayMatch: DayMatchOr, expected: time.Date(2024, time.May, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "wildcard day of week", cronExpr: "0 0 15 * * cmd", dayMatch: DayMatchOr, expected: time.Date(2024, time.May, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "stepped wildcard needs both", cronExpr: "0 0 */10 * Mon cmd", dayMatch: DayMatchOr, expected: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range dayMatchTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, WithDayMatch(tc.dayMatch))
+			assertSuccess(t, schedule.Next(from), tc.expected, err)
+		})
+	}
+
+	t.Run("matches either day field", func(t *testing.T) {
+		schedule, err := Parse("0 0 13 * Fri cmd")
+		friday := time.Date(2024, time.May, 3, 0, 0, 0, 0, time.UTC)
+		thirteenth := time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC)
+		assertSuccess(t, schedule.Matches(friday) && schedule.Matches(thirteenth), true, err)
+	})
+}
+
+func TestIsWildcard(t *testing.T) {
+	schedule, err := Parse("*/15 0 * 1-6 Mon cmd")
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	expected := map[FieldKind]bool{FieldMinute: true, FieldHour: false, FieldDayOfMonth: true, FieldMonth: false, FieldDayOfWeek: false}
+	for field, wildcard := range expected {
+		t.Run(field.String(), func(t *testing.T) {
+			assertSuccess(t, schedule.IsWildcard(field), wildcard, nil)
+		})
+	}
+}
+
+func TestIsRandom(t *testing.T) {
+	schedule, err := Parse("0~30 0 * 1-6 ~ cmd")
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	expected := map[FieldKind]bool{FieldMinute: true, FieldHour: false, FieldDayOfMonth: false, FieldMonth: false, FieldDayOfWeek: true}
+	for field, random := range expected {
+		t.Run(field.String(), func(t *testing.T) {
+			assertSuccess(t, schedule.IsRandom(field), random, nil)
+		})
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	schedule, err := Parse("*/20 0 12 * * * cmd", WithSeconds())
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	from := time.Date(2024, time.May, 5, 12, 0, 20, 0, time.UTC)
+
+	t.Run("next", func(t *testing.T) {
+		assertSuccess(t, schedule.Next(from), time.Date(2024, time.May, 5, 12, 0, 40, 0, time.UTC), nil)
+	})
+
+	t.Run("next minute", func(t *testing.T) {
+		assertSuccess(t, schedule.Next(from.Add(30*time.Second)), time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC), nil)
+	})
+
+	t.Run("prev", func(t *testing.T) {
+		assertSuccess(t, schedule.Prev(from), time.Date(2024, time.May, 5, 12, 0, 0, 0, time.UTC), nil)
+	})
+
+	t.Run("matches to the second", func(t *testing.T) {
+		assertSuccess(t, schedule.Matches(from.Add(500*time.Millisecond)) && !schedule.Matches(from.Add(time.Second)), true, nil)
+	})
+
+	t.Run("between", func(t *testing.T) {
+		got := schedule.Between(from, from.AddDate(0, 0, 1), 0)
+		expected := []time.Time{from, from.Add(20 * time.Second), time.Date(2024, time.May, 6, 12, 0, 0, 0, time.UTC)}
+		assertSuccess(t, got, expected, nil)
+	})
+}
+
+func TestYear(t *testing.T) {
+	from := time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)
+
+	yearTestCases := []struct {
+		name     string
+		cronExpr string
+		next     time.Time
+		prev     time.Time
+	}{
+		{name: "future years", cronExpr: "0 0 1 1 * 2026,2028 cmd", next: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), prev: time.Time{}},
+		{name: "current year", cronExpr: "0 0 1 * * 2024 cmd", next: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC), prev: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "past years never fire again", cronExpr: "0 0 * * * 1970-2023 cmd", next: time.Time{}, prev: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "impossible in the given years", cronExpr: "0 0 29 2 * 2025-2027 cmd", next: time.Time{}, prev: time.Time{}},
+	}
+
+	for _, tc := range yearTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, WithYear())
+			assertSuccess(t, schedule.Next(from), tc.next, err)
+			assertSuccess(t, schedule.Prev(from), tc.prev, err)
+		})
+	}
+
+	t.Run("matches", func(t *testing.T) {
+		schedule, err := Parse("0 0 1 1 * 2026 cmd", WithYear())
+		assertSuccess(t, schedule.Matches(time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)), true, err)
+		assertSuccess(t, schedule.Matches(time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)), false, err)
+	})
+}
+
+func TestReboot(t *testing.T) {
+	schedule, err := Parse("@reboot /usr/bin/start")
+	if err != nil {
+		t.Fatal("error is not expected here: ", err)
+	}
+
+	from := time.Date(2024, time.May, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("kind", func(t *testing.T) {
+		assertSuccess(t, schedule.Kind(), KindReboot, nil)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		assertSuccess(t, schedule.String(), "event\t\t@reboot\ncommand\t\t/usr/bin/start", nil)
+	})
+
+	t.Run("no calendar occurrences", func(t *testing.T) {
+		assertSuccess(t, schedule.Next(from).IsZero() && schedule.Prev(from).IsZero(), true, nil)
+		assertSuccess(t, schedule.Matches(from), false, nil)
+		assertSuccess(t, len(schedule.Between(from, from.AddDate(1, 0, 0), 0)), 0, nil)
+	})
+
+	t.Run("calendar kind", func(t *testing.T) {
+		schedule, err := Parse("@daily cmd")
+		assertSuccess(t, schedule.Kind(), KindCalendar, err)
+	})
+}
+
+func TestExpression(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cronExpr string
+		opts     []Option
+		expected string
+	}{
+		{name: "spacing and case", cronExpr: " 00\t9-017  1,15 jan-Mar mon-fri/2 /usr/bin/find  -x", expected: "0 9-17 1,15 JAN-MAR MON-FRI/2 /usr/bin/find  -x"},
+		{name: "asterisks", cronExpr: "*/05 * ? * * cmd", expected: "*/5 * ? * * cmd"},
+		{name: "quartz", cronExpr: "0 0 l,L-03,lw,015w * ? cmd", expected: "0 0 L,L-3,LW,15W * ? cmd"},
+		{name: "quartz day of week", cronExpr: "0 0 ? * fril,5l,l,Fri#3 cmd", expected: "0 0 ? * FRIL,5L,L,FRI#3 cmd"},
+		{name: "hash", cronExpr: "h(0-029)/10 H * * * cmd", opts: []Option{WithHashKey("job")}, expected: "H(0-29)/10 H * * * cmd"},
+		{name: "random", cronExpr: "~ 0~05,12 * * 5~7 cmd", opts: []Option{WithRand(rand.New(rand.NewSource(1)))}, expected: "41 3,12 * * 0 cmd"},
+		{name: "seconds and year", cronExpr: "30 0 0 1 1 * 2030 cmd", opts: []Option{WithSeconds(), WithYear()}, expected: "30 0 0 1 1 * 2030 cmd"},
+		{name: "macro", cronExpr: "@Daily cmd", expected: "0 0 * * * cmd"},
+		{name: "time zone", cronExpr: "TZ=Asia/Tokyo 0 9 * * * cmd", expected: "CRON_TZ=Asia/Tokyo 0 9 * * * cmd"},
+		{name: "utc", cronExpr: "CRON_TZ=UTC 0 9 * * * cmd", expected: "0 9 * * * cmd"},
+		{name: "command and stdin", cronExpr: "0 9 * * * mail -s 100\\% x%Dear%all", expected: "0 9 * * * mail -s 100\\% x%Dear%all"},
+		{name: "reboot", cronExpr: "TZ=Asia/Tokyo @reboot  cmd%in", expected: "CRON_TZ=Asia/Tokyo @reboot cmd%in"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, tc.opts...)
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			assertSuccess(t, schedule.Expression(), tc.expected, nil)
+		})
+	}
+
+	for _, tc := range testCases {
+		t.Run("round trip "+tc.name, func(t *testing.T) {
+			schedule, err := Parse(tc.cronExpr, tc.opts...)
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			reparsed, err := Parse(schedule.Expression(), tc.opts...)
+			if err != nil {
+				t.Fatal("error is not expected here: ", err)
+			}
+
+			assertSuccess(t, reparsed.Expression(), schedule.Expression(), nil)
+
+			//only the fields as written, and whether values were picked, may differ
+			schedule.fields, reparsed.fields = nil, nil
+			schedule.random, reparsed.random = nil, nil
+			schedule.picked, reparsed.picked = nil, nil
+			assertSuccess(t, reparsed, schedule, nil)
+		})
+	}
+
+	t.Run("zone without a name to load", func(t *testing.T) {
+		loc := time.FixedZone("X", 3600)
+		schedule, err := ParseInLocation("0 0 * * * cmd", loc)
+		assertSuccess(t, schedule.Expression(), "0 0 * * * cmd", err)
+
+		reparsed, err := ParseInLocation(schedule.Expression(), loc)
+		schedule.fields, reparsed.fields = nil, nil
+		assertSuccess(t, reparsed, schedule, err)
+	})
+
+	t.Run("random term with no picked value", func(t *testing.T) {
+		schedule, err := Parse("0~30 0 * * * cmd", WithRand(rand.New(rand.NewSource(1))))
+		schedule.picked = nil
+		assertSuccess(t, schedule.Expression(), "0~30 0 * * * cmd", err)
+	})
+}
@@ -0,0 +1,127 @@
+package cronparser
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NAME_ALIASES maps names often written in place of an abbreviation to the
+// abbreviation meant.
+var NAME_ALIASES = map[string]string{"TUES": "TUE", "WEDS": "WED", "THUR": "THU", "THURS": "THU", "SEPT": "SEP"}
+
+// suggest fills in the Suggestion of a ParseError in field for misspelled
+// names and common mistakes such as "*/60" or a month counted from 0.
+func suggest(parseErr *ParseError, field FieldKind) {
+	token := strings.ToUpper(parseErr.Token)
+
+	switch {
+	case errors.Is(parseErr.Err, ErrInvalidValue):
+		parseErr.Suggestion = suggestName(token, field.abbreviations())
+	case errors.Is(parseErr.Err, ErrInvalidStep):
+		parseErr.Suggestion = suggestStep(token, field.bounds())
+	case errors.Is(parseErr.Err, ErrOutOfBounds) && field == FieldMonth:
+		parseErr.Suggestion = suggestMonth(token)
+	case errors.Is(parseErr.Err, ErrOutOfBounds) && (field == FieldSecond || field == FieldMinute || field == FieldHour):
+		//60 minutes past the hour is the next hour's 0, as 24 o'clock is midnight
+		if val, err := strconv.Atoi(token); err == nil && val == field.bounds().max+1 {
+			parseErr.Suggestion = strconv.Itoa(field.bounds().min)
+		}
+	}
+}
+
+// suggestName returns the name in abbreviationMap closest to token, if any is
+// close enough to be a likely misspelling.
+func suggestName(token string, abbreviationMap map[string]string) string {
+	if alias, ok := NAME_ALIASES[token]; ok {
+		if _, ok := abbreviationMap[alias]; ok {
+			return alias
+		}
+	}
+
+	names := make([]string, 0, len(abbreviationMap))
+	for name := range abbreviationMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	//allow a typo for every three letters, and at least one
+	maxDistance := len(token) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	suggestion, bestDistance := "", maxDistance+1
+	for _, name := range names {
+		if distance := editDistance(token, name); distance < bestDistance {
+			suggestion, bestDistance = name, distance
+		}
+	}
+
+	return suggestion
+}
+
+// suggestStep suggests dropping a step of 0, or one as long as the field or
+// longer, such as "*/60" or "*/90" in minutes, which could only ever match its start.
+func suggestStep(token string, bounds bound) string {
+	tokens, err := lex(token)
+	i := indexToken(tokens, tokenSlash)
+	if err != nil || i == -1 || !matchTokens(tokens[i:], tokenSlash, tokenNumber) {
+		return ""
+	}
+
+	interval, err := strconv.Atoi(tokens[i+1].text)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case interval == 0:
+		return joinTokens(tokens[:i])
+	case interval < bounds.max-bounds.min+1:
+		return ""
+	case tokens[0].kind == tokenAsterisk:
+		return strconv.Itoa(bounds.min)
+	case tokens[0].kind == tokenNumber || tokens[0].kind == tokenName:
+		//the start of a range, as in "0-6/7" or "MON/7"
+		return tokens[0].text
+	}
+
+	return ""
+}
+
+// suggestMonth suggests counting months from 1 when token counts them from 0,
+// as in "0-11" or "0/3".
+func suggestMonth(token string) string {
+	tokens, err := lex(token)
+	if err != nil {
+		return ""
+	}
+
+	hasZero := false
+	for i, tok := range tokens {
+		if tok.kind == tokenSlash {
+			break
+		}
+
+		if tok.kind != tokenNumber {
+			continue
+		}
+
+		val, err := strconv.Atoi(tok.text)
+		if err != nil || val+1 > MonthBound.max {
+			return ""
+		}
+
+		hasZero = hasZero || val == 0
+		tokens[i].text = strconv.Itoa(val + 1)
+	}
+
+	if !hasZero {
+		return ""
+	}
+
+	return joinTokens(tokens)
+}
@@ -0,0 +1,64 @@
+package cronparser
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	suggestTestCases := []struct {
+		name     string
+		field    FieldKind
+		err      *ParseError
+		expected string
+	}{
+		{name: "misspelled day", field: FieldDayOfWeek, err: &ParseError{Token: "MOM", Err: ErrInvalidValue}, expected: "MON"},
+		{name: "misspelled full month", field: FieldMonth, err: &ParseError{Token: "Septmber", Err: ErrInvalidValue}, expected: "SEPTEMBER"},
+		{name: "month alias", field: FieldMonth, err: &ParseError{Token: "Sept", Err: ErrInvalidValue}, expected: "SEP"},
+		{name: "day alias", field: FieldDayOfWeek, err: &ParseError{Token: "thur", Err: ErrInvalidValue}, expected: "THU"},
+		{name: "alias in another field", field: FieldDayOfWeek, err: &ParseError{Token: "SEPT", Err: ErrInvalidValue}, expected: ""},
+		{name: "nothing close", field: FieldMonth, err: &ParseError{Token: "XYZ", Err: ErrInvalidValue}, expected: ""},
+		{name: "60 minutes", field: FieldMinute, err: &ParseError{Token: "60", Err: ErrOutOfBounds}, expected: "0"},
+		{name: "24 hours", field: FieldHour, err: &ParseError{Token: "24", Err: ErrOutOfBounds}, expected: "0"},
+		{name: "far out of bounds", field: FieldMinute, err: &ParseError{Token: "75", Err: ErrOutOfBounds}, expected: ""},
+		{name: "zero step", field: FieldMinute, err: &ParseError{Token: "1-5/0", Err: ErrInvalidStep}, expected: "1-5"},
+		{name: "step longer than the field", field: FieldMinute, err: &ParseError{Token: "*/90", Err: ErrInvalidStep}, expected: "0"},
+		{name: "step as long as the field", field: FieldMinute, err: &ParseError{Token: "*/60", Err: ErrInvalidStep}, expected: "0"},
+		{name: "step longer than the field from a start", field: FieldHour, err: &ParseError{Token: "6/48", Err: ErrInvalidStep}, expected: "6"},
+		{name: "step as long as the week from a name", field: FieldDayOfWeek, err: &ParseError{Token: "mon/7", Err: ErrInvalidStep}, expected: "MON"},
+		{name: "step from a range", field: FieldDayOfWeek, err: &ParseError{Token: "1-4/7", Err: ErrInvalidStep}, expected: "1"},
+		{name: "step within the field", field: FieldMinute, err: &ParseError{Token: "*/15", Err: ErrInvalidStep}, expected: ""},
+		{name: "unknown character in a step", field: FieldMinute, err: &ParseError{Token: "*/6!", Err: ErrInvalidStep}, expected: ""},
+		{name: "month from 0", field: FieldMonth, err: &ParseError{Token: "0-11", Err: ErrOutOfBounds}, expected: "1-12"},
+		{name: "stepped month from 0", field: FieldMonth, err: &ParseError{Token: "0/3", Err: ErrOutOfBounds}, expected: "1/3"},
+		{name: "month range from 0 with a step", field: FieldMonth, err: &ParseError{Token: "0-5/2", Err: ErrOutOfBounds}, expected: "1-6/2"},
+		{name: "month past december", field: FieldMonth, err: &ParseError{Token: "13", Err: ErrOutOfBounds}, expected: ""},
+	}
+
+	for _, tc := range suggestTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			suggest(tc.err, tc.field)
+			assertSuccess(t, tc.err.Suggestion, tc.expected, nil)
+		})
+	}
+}
+
+func TestParseSuggestion(t *testing.T) {
+	failureTestCases := []struct {
+		name     string
+		cronExpr string
+		expected string
+	}{
+		{name: "FC: misspelled day", cronExpr: "0 0 * * MOM cmd", expected: "Parsing Error: day of week field \"MOM\": invalid value (did you mean \"MON\"?)"},
+		{name: "FC: month alias", cronExpr: "0 0 1 Sept * cmd", expected: "Parsing Error: month field \"Sept\": invalid value (did you mean \"SEP\"?)"},
+		{name: "FC: month from 0", cronExpr: "0 0 1 0-11 * cmd", expected: "Parsing Error: month field \"0-11\": invalid value, out of bounds (did you mean \"1-12\"?)"},
+		{name: "FC: step as long as the field", cronExpr: "*/60 * * * * cmd", expected: "Parsing Error: minute field \"*/60\": invalid interval (did you mean \"0\"?)"},
+		{name: "FC: whole range stepped by its length", cronExpr: "0 0 * * 0-6/7 cmd", expected: "Parsing Error: day of week field \"0-6/7\": invalid interval (did you mean \"0\"?)"},
+		{name: "FC: hour step as long as the day", cronExpr: "0 */24 * * * cmd", expected: "Parsing Error: hour field \"*/24\": invalid interval (did you mean \"0\"?)"},
+		{name: "FC: misspelled macro", cronExpr: "@hourley cmd", expected: "Validation Error: invalid macro (did you mean \"@HOURLY\"?)"},
+	}
+
+	for _, tc := range failureTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.cronExpr)
+			assertError(t, err, tc.expected)
+		})
+	}
+}
@@ -1,10 +1,107 @@
 package cronparser
 
 import (
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
+// splitFields splits the first n fields off cronExpr at runs of whitespace,
+// and returns them followed by the rest of the line, kept verbatim, along
+// with the byte offset each one starts at.
+func splitFields(cronExpr string, n int) ([]string, []int) {
+	var fields []string
+	var offsets []int
+	rest := strings.TrimLeftFunc(cronExpr, unicode.IsSpace)
+	for len(fields) < n && rest != "" {
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end == -1 {
+			end = len(rest)
+		}
+
+		fields = append(fields, rest[:end])
+		offsets = append(offsets, len(cronExpr)-len(rest))
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+
+	if rest != "" {
+		fields = append(fields, rest)
+		offsets = append(offsets, len(cronExpr)-len(rest))
+	}
+
+	return fields, offsets
+}
+
+// editDistance counts the single letter insertions, deletions and
+// substitutions that turn a into b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// uniqueInts sorts ints and drops repeated values.
+func uniqueInts(ints []int) []int {
+	sort.Ints(ints)
+
+	unique := ints[:0]
+	for _, v := range ints {
+		if len(unique) == 0 || v != unique[len(unique)-1] {
+			unique = append(unique, v)
+		}
+	}
+
+	return unique
+}
+
+// commandLines prints a command, followed by its standard input if it has one.
+func commandLines(cmd, stdin string) string {
+	lines := "command\t\t" + cmd
+	if stdin != "" {
+		lines += "\nstdin\t\t" + strings.ReplaceAll(stdin, "\n", "\n\t\t")
+	}
+
+	return lines
+}
+
+// commandExpr writes a command and its standard input back the way
+// handleCommand reads them, with % as separator and "\%" for a literal %.
+func commandExpr(cmd, stdin string) string {
+	expr := strings.ReplaceAll(cmd, "%", `\%`)
+	if stdin != "" {
+		expr += "%" + strings.ReplaceAll(strings.ReplaceAll(stdin, "%", `\%`), "\n", "%")
+	}
+
+	return expr
+}
+
 func buildIntList(min, max, interval int) []int {
 	var intList []int
 	for i := min; i <= max; i += interval {
@@ -14,6 +111,21 @@ func buildIntList(min, max, interval int) []int {
 	return intList
 }
 
+// buildWrappedIntList steps from min past the end of bounds and on to max,
+// returning the values in ascending order.
+func buildWrappedIntList(min, max, interval int, bounds bound) []int {
+	span := bounds.max - bounds.min + 1
+	length := max - min + span
+
+	var intList []int
+	for i := 0; i <= length; i += interval {
+		intList = append(intList, bounds.min+(min-bounds.min+i)%span)
+	}
+
+	sort.Ints(intList)
+	return intList
+}
+
 func intsJoin(ints []int, sep string) string {
 	strInts := make([]string, len(ints))
 	for i, v := range ints {
@@ -22,3 +134,95 @@ func intsJoin(ints []int, sep string) string {
 
 	return strings.Join(strInts, sep)
 }
+
+// dayFieldJoin prints the expanded values of a day field followed by its
+// month dependent ones.
+func dayFieldJoin(ints []int, specs []daySpec) string {
+	if len(specs) == 0 {
+		return intsJoin(ints, " ")
+	}
+
+	if len(ints) == 0 {
+		return daySpecsJoin(specs, " ")
+	}
+
+	return intsJoin(ints, " ") + " " + daySpecsJoin(specs, " ")
+}
+
+func containsInt(ints []int, val int) bool {
+	i := sort.SearchInts(ints, val)
+	return i < len(ints) && ints[i] == val
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// ordered returns ints in search order: as is when forward, reversed otherwise.
+func ordered(ints []int, forward bool) []int {
+	if forward {
+		return ints
+	}
+
+	reversed := make([]int, len(ints))
+	for i, v := range ints {
+		reversed[len(ints)-1-i] = v
+	}
+
+	return reversed
+}
+
+// precedes reports whether val comes before start in the search direction.
+func precedes(val, start int, forward bool) bool {
+	if forward {
+		return val < start
+	}
+
+	return val > start
+}
+
+// civil returns the wall clock reading of t as a UTC time, so that calendar
+// arithmetic on it is free of time zone transitions.
+func civil(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func containsTime(times []time.Time, t time.Time) bool {
+	for _, v := range times {
+		if v.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reversedTimes(times []time.Time) []time.Time {
+	reversed := make([]time.Time, len(times))
+	for i, v := range times {
+		reversed[len(times)-1-i] = v
+	}
+
+	return reversed
+}
+
+// ahead reports whether t comes strictly after from in the search direction.
+func ahead(t, from time.Time, forward bool) bool {
+	if forward {
+		return t.After(from)
+	}
+
+	return t.Before(from)
+}
+
+// firesAt reports whether t falls within resolution of one of the activations.
+func firesAt(activations []time.Time, t time.Time, resolution time.Duration) bool {
+	for _, activation := range activations {
+		if !t.Before(activation) && t.Sub(activation) < resolution {
+			return true
+		}
+	}
+
+	return false
+}
@@ -3,9 +3,39 @@ package cronparser
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestUtitlity(t *testing.T) {
+	t.Run("split fields", func(t *testing.T) {
+		got, offsets := splitFields(" 1\t2  3 cmd  -x ", 3)
+		expected := []string{"1", "2", "3", "cmd  -x "}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %q but got %q", expected, got)
+		}
+
+		expectedOffsets := []int{1, 3, 6, 8}
+		if !reflect.DeepEqual(offsets, expectedOffsets) {
+			t.Errorf("expected %v but got %v", expectedOffsets, offsets)
+		}
+	})
+
+	t.Run("edit distance", func(t *testing.T) {
+		got := []int{editDistance("MOM", "MON"), editDistance("SEPT", "SEP"), editDistance("", "JAN"), editDistance("KITTEN", "SITTING")}
+		expected := []int{1, 1, 3, 3}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
+	t.Run("unique ints", func(t *testing.T) {
+		got := uniqueInts([]int{5, 1, 5, 0, 1})
+		expected := []int{0, 1, 5}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
 	t.Run("build int list", func(t *testing.T) {
 		got := buildIntList(0, 6, 2)
 		expected := []int{0, 2, 4, 6}
@@ -14,6 +44,14 @@ func TestUtitlity(t *testing.T) {
 		}
 	})
 
+	t.Run("build wrapped int list", func(t *testing.T) {
+		got := buildWrappedIntList(22, 2, 2, HourBound)
+		expected := []int{0, 2, 22}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
+
 	t.Run("join ints list ", func(t *testing.T) {
 		got := intsJoin([]int{1, 2, 3, 4}, ",")
 		expected := "1,2,3,4"
@@ -23,6 +61,21 @@ func TestUtitlity(t *testing.T) {
 		}
 
 	})
+
+	t.Run("contains int", func(t *testing.T) {
+		ints := []int{1, 4, 9}
+		if !containsInt(ints, 4) || containsInt(ints, 5) || containsInt(ints, 10) {
+			t.Errorf("unexpected membership in %v", ints)
+		}
+	})
+
+	t.Run("days in month", func(t *testing.T) {
+		got := []int{daysIn(2023, time.February), daysIn(2024, time.February), daysIn(2024, time.April), daysIn(2024, time.December)}
+		expected := []int{28, 29, 30, 31}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v but got %v", expected, got)
+		}
+	})
 }
 
 func assertSuccess(t testing.TB, got, expected interface{}, err error) {